@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rowCountMaxAge is how long an approximate count is adjusted from writes before
+// ApproxRowCount recounts, to correct drift from writes the Exec helpers didn't see
+const rowCountMaxAge = 10 * time.Minute
+
+// rowCountCache holds approximate per-table row counts
+type rowCountCache struct {
+	mu     sync.Mutex
+	counts map[string]*rowCount // Keyed on lowercased table name
+}
+
+// rowCount is a table's last exact count, adjusted by writes since
+type rowCount struct {
+	n         int64
+	countedAt time.Time
+}
+
+// newRowCountCache creates an empty row count cache
+func newRowCountCache() *rowCountCache {
+	return &rowCountCache{counts: make(map[string]*rowCount)}
+}
+
+// ApproxRowCount returns table's row count for display. The first call counts exactly;
+// later calls return that count adjusted by the inserts and deletes run through
+// ExecContext and Exec since, recounting once it is older than rowCountMaxAge. Writes
+// made inside transactions or on other connections only show up at the next recount.
+func (d *LibSQLDatabase) ApproxRowCount(ctx context.Context, table string) (int64, error) {
+	d.rowCounts.mu.Lock()
+	cached, ok := d.rowCounts.counts[strings.ToLower(table)]
+	var n int64
+	if ok {
+		n = cached.n
+		ok = time.Since(cached.countedAt) < rowCountMaxAge
+	}
+	d.rowCounts.mu.Unlock()
+
+	if ok {
+		return max(n, 0), nil
+	}
+	return d.RefreshRowCount(ctx, table)
+}
+
+// RefreshRowCount counts table's rows exactly and caches the result for ApproxRowCount
+func (d *LibSQLDatabase) RefreshRowCount(ctx context.Context, table string) (int64, error) {
+	var n int64
+	err := d.QueryRow(ctx, "row_count", "SELECT COUNT(*) FROM "+quoteIdent(table)).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count rows in %s: %w", table, err)
+	}
+
+	d.rowCounts.mu.Lock()
+	d.rowCounts.counts[strings.ToLower(table)] = &rowCount{n: n, countedAt: time.Now()}
+	d.rowCounts.mu.Unlock()
+
+	return n, nil
+}
+
+// tracking reports whether any table is cached, so writes can skip parsing otherwise
+func (c *rowCountCache) tracking() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.counts) > 0
+}
+
+// reset drops every cached count, such as after the data was replaced wholesale
+func (c *rowCountCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.counts)
+}
+
+// adjust applies a write's affected row count to the cached count of the table it
+// touched. Only plain INSERT, REPLACE and DELETE statements are recognized; an upsert
+// that updates rather than inserts still counts as an insert, which is fine for display.
+func (c *rowCountCache) adjust(query string, affected int64) {
+	table, delta := writeTarget(query)
+	if table == "" || affected == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if cached, ok := c.counts[strings.ToLower(table)]; ok {
+		cached.n += delta * affected
+	}
+}
+
+// writeTarget finds the table an INSERT, REPLACE or DELETE writes to and whether it adds
+// (+1) or removes (-1) rows. Other statements return an empty table.
+func writeTarget(query string) (string, int64) {
+	words := strings.Fields(strings.ReplaceAll(stripSQLComments(query), "(", " ("))
+	if len(words) < 3 {
+		return "", 0
+	}
+
+	switch strings.ToUpper(words[0]) {
+	case "INSERT", "REPLACE":
+		for i, word := range words[:len(words)-1] {
+			if strings.EqualFold(word, "INTO") {
+				return tableName(words[i+1]), 1
+			}
+		}
+	case "DELETE":
+		if strings.EqualFold(words[1], "FROM") {
+			return tableName(words[2]), -1
+		}
+	}
+	return "", 0
+}
+
+// tableName strips a schema prefix and identifier quoting from a table reference
+func tableName(ref string) string {
+	if i := strings.LastIndexByte(ref, '.'); i >= 0 {
+		ref = ref[i+1:]
+	}
+	return strings.Trim(ref, "\"`[]")
+}
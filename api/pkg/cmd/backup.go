@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// BackupTo writes a consistent online copy of a local database to destPath using VACUUM INTO
+func (d *LibSQLDatabase) BackupTo(ctx context.Context, destPath string) error {
+	if !isLocalFile(d.config.URL) {
+		return fmt.Errorf("backup is only supported for local file databases")
+	}
+
+	// VACUUM INTO refuses to overwrite an existing database
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup destination %s already exists", destPath)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to stat backup destination: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o750); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if _, err := d.db.ExecContext(ctx, "VACUUM INTO ?", destPath); err != nil {
+		return fmt.Errorf("failed to back up database to %s: %w", destPath, err)
+	}
+
+	d.logger.Info("database backup complete", "path", destPath)
+	return nil
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupTo(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, testConfig(t))
+
+	if _, err := db.Exec(ctx, "create_table", "CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	const rows = 100
+	for i := range rows {
+		if _, err := db.Exec(ctx, "insert", "INSERT INTO items (name) VALUES (?)", i); err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	dest := filepath.Join(t.TempDir(), "backups", "copy.db")
+	if err := db.BackupTo(ctx, dest); err != nil {
+		t.Fatalf("BackupTo: %v", err)
+	}
+
+	cfg := testConfig(t)
+	cfg.URL = "file:" + dest
+	backup := openTestDB(t, cfg)
+
+	var got int
+	if err := backup.QueryRow(ctx, "count", "SELECT COUNT(*) FROM items").Scan(&got); err != nil {
+		t.Fatalf("count backup rows: %v", err)
+	}
+	if got != rows {
+		t.Errorf("backup has %d rows, want %d", got, rows)
+	}
+
+	if err := db.BackupTo(ctx, dest); err == nil {
+		t.Error("BackupTo over an existing file succeeded, want an error")
+	}
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ExecBatch runs statements in order inside one transaction. Entries that are empty or
+// hold only comments are skipped. The first failure rolls everything back and the error
+// names the statement's index in statements and its SQL.
+func (d *LibSQLDatabase) ExecBatch(ctx context.Context, statements []string) error {
+	return d.Transaction(ctx, func(tx *sql.Tx) error {
+		for i, entry := range statements {
+			// Splitting also drops comment-only entries
+			for _, stmt := range splitStatements(entry) {
+				start := time.Now()
+				_, err := tx.ExecContext(ctx, stmt)
+				d.observeQuery(ctx, "exec_batch", stmt, time.Since(start), err)
+				if err != nil {
+					return fmt.Errorf("batch statement %d failed (%s): %w", i, truncateStatement(stmt), err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Statement is a single parameterized statement for BatchExec
+type Statement struct {
+	SQL  string // Statement text
+	Args []any  // Positional or sql.Named arguments
+}
+
+// BatchResult is the outcome of one statement in a BatchExec
+type BatchResult struct {
+	RowsAffected int64 // Rows changed by the statement
+	LastInsertID int64 // Rowid of the last insert, if the statement inserted
+}
+
+// BatchExec runs stmts atomically inside one transaction and returns one result per
+// statement. The first failure rolls everything back and the error names the
+// statement's index in stmts and its SQL.
+//
+// go-libsql's database/sql driver exposes no batch hook, so there is no native
+// single-request path: against a remote database each statement is still its own round
+// trip.
+func (d *LibSQLDatabase) BatchExec(ctx context.Context, stmts []Statement) ([]BatchResult, error) {
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+
+	results := make([]BatchResult, 0, len(stmts))
+	err := d.Transaction(ctx, func(tx *sql.Tx) error {
+		for i, stmt := range stmts {
+			start := time.Now()
+			result, err := tx.ExecContext(ctx, stmt.SQL, stmt.Args...)
+			d.observeQuery(ctx, "batch_exec", stmt.SQL, time.Since(start), err)
+			if err != nil {
+				return fmt.Errorf("batch statement %d failed (%s): %w", i, truncateStatement(stmt.SQL), err)
+			}
+			results = append(results, batchResult(result))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// batchResult reads the counts out of a statement result. Drivers that can't report
+// them leave the fields zero.
+func batchResult(result sql.Result) BatchResult {
+	var br BatchResult
+	if affected, err := result.RowsAffected(); err == nil {
+		br.RowsAffected = affected
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		br.LastInsertID = id
+	}
+	return br
+}
@@ -0,0 +1,134 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// BreakerState is the state of the circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Requests flow normally
+	BreakerHalfOpen                     // Cooldown elapsed; the next probe decides
+	BreakerOpen                         // Requests fail fast with ErrCircuitOpen
+)
+
+// String returns the state name
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	default:
+		return "unknown"
+	}
+}
+
+// circuitBreaker trips after consecutive failed health probes. A nil breaker is
+// always closed, which is how the feature is disabled.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	openedAt  time.Time
+	threshold int
+	cooldown  time.Duration
+}
+
+// newCircuitBreaker returns a breaker, or nil when threshold disables it
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown}
+}
+
+// BreakerState reports the current circuit breaker state
+func (d *LibSQLDatabase) BreakerState() BreakerState {
+	return d.breaker.current()
+}
+
+// allow returns ErrCircuitOpen while the breaker is open
+func (b *circuitBreaker) allow() error {
+	if b.current() == BreakerOpen {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// current returns the state, moving from open to half-open once the cooldown elapses
+func (b *circuitBreaker) current() BreakerState {
+	if b == nil {
+		return BreakerClosed
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+	}
+	return b.state
+}
+
+// record feeds a probe result into the breaker and returns the previous and new states
+func (b *circuitBreaker) record(ok bool) (from, to BreakerState) {
+	from = b.current()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if ok {
+		b.failures = 0
+		b.state = BreakerClosed
+		return from, b.state
+	}
+
+	b.failures++
+	// A failed probe while half-open reopens immediately
+	if from == BreakerHalfOpen || b.failures >= b.threshold {
+		if b.state != BreakerOpen {
+			b.openedAt = time.Now()
+		}
+		b.state = BreakerOpen
+	}
+	return from, b.state
+}
+
+// recordBreakerProbe updates the breaker from a health probe run under ctx and reports
+// transitions
+func (d *LibSQLDatabase) recordBreakerProbe(ctx context.Context, err error) {
+	if d.breaker == nil {
+		return
+	}
+
+	// A probe that hit HealthTimeout while ctx was still live is how a hung primary shows
+	// up, so it counts even though it classifies as cancelled. Otherwise cancellations and
+	// constraint violations are answers from a working database (or none at all), so they
+	// neither trip nor reset the breaker.
+	if err != nil && !(errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil) {
+		if class := d.classify(err); class == ErrorClassCancelled || class == ErrorClassConstraint {
+			return
+		}
+	}
+
+	from, to := d.breaker.record(err == nil)
+	if d.metrics != nil {
+		d.metrics.breakerState.Set(float64(to))
+	}
+	if from == to {
+		return
+	}
+
+	if to == BreakerOpen {
+		d.logger.Error("circuit breaker opened", "from", from.String(), "error", err)
+	} else {
+		d.logger.Info("circuit breaker state changed", "from", from.String(), "to", to.String())
+	}
+}
@@ -0,0 +1,118 @@
+package database
+
+import "strings"
+
+// SelectBuilder builds parameterized SELECT statements with dynamic WHERE clauses.
+// Conditions, columns and ORDER BY terms are written into the SQL as given and must be
+// static strings; every user-supplied value goes through args.
+type SelectBuilder struct {
+	table   string
+	columns []string
+	where   []string
+	args    []any
+	orderBy []string
+	limit   int
+	offset  int
+
+	softDelete     bool // Filter out soft-deleted rows
+	includeDeleted bool // Skip the soft-delete filter
+}
+
+// NewSelect starts a SELECT against table
+func NewSelect(table string) *SelectBuilder {
+	return &SelectBuilder{table: table}
+}
+
+// NewSoftDeleteSelect starts a SELECT that excludes rows whose deleted_at is set
+func NewSoftDeleteSelect(table string) *SelectBuilder {
+	return &SelectBuilder{table: table, softDelete: true}
+}
+
+// IncludeDeleted disables the soft-delete filter for this query
+func (b *SelectBuilder) IncludeDeleted() *SelectBuilder {
+	b.includeDeleted = true
+	return b
+}
+
+// Columns sets the selected columns (default *)
+func (b *SelectBuilder) Columns(columns ...string) *SelectBuilder {
+	b.columns = append(b.columns, columns...)
+	return b
+}
+
+// Where adds a condition joined with AND; cond uses ? placeholders for args
+func (b *SelectBuilder) Where(cond string, args ...any) *SelectBuilder {
+	b.where = append(b.where, "("+cond+")")
+	b.args = append(b.args, args...)
+	return b
+}
+
+// WhereIf adds the condition only when ok is true
+func (b *SelectBuilder) WhereIf(ok bool, cond string, args ...any) *SelectBuilder {
+	if !ok {
+		return b
+	}
+	return b.Where(cond, args...)
+}
+
+// OrderBy appends ORDER BY terms such as "created_at DESC"
+func (b *SelectBuilder) OrderBy(terms ...string) *SelectBuilder {
+	b.orderBy = append(b.orderBy, terms...)
+	return b
+}
+
+// Limit caps the number of rows returned (0 means no limit)
+func (b *SelectBuilder) Limit(n int) *SelectBuilder {
+	b.limit = n
+	return b
+}
+
+// Offset skips the first n rows
+func (b *SelectBuilder) Offset(n int) *SelectBuilder {
+	b.offset = n
+	return b
+}
+
+// Build returns the query string and its arguments, ready for Query
+func (b *SelectBuilder) Build() (string, []any) {
+	var sb strings.Builder
+	args := append([]any(nil), b.args...)
+
+	sb.WriteString("SELECT ")
+	if len(b.columns) == 0 {
+		sb.WriteString("*")
+	} else {
+		sb.WriteString(strings.Join(b.columns, ", "))
+	}
+	sb.WriteString(" FROM ")
+	sb.WriteString(quoteIdent(b.table))
+
+	where := b.where
+	if b.softDelete && !b.includeDeleted {
+		where = append(where[:len(where):len(where)], quoteIdent(softDeleteColumn)+" IS NULL")
+	}
+	if len(where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(where, " AND "))
+	}
+	if len(b.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(b.orderBy, ", "))
+	}
+
+	// SQLite requires LIMIT before OFFSET; -1 means unbounded
+	if b.limit > 0 || b.offset > 0 {
+		limit := b.limit
+		if limit <= 0 {
+			limit = -1
+		}
+		sb.WriteString(" LIMIT ?")
+		args = append(args, limit)
+	}
+	if b.offset > 0 {
+		sb.WriteString(" OFFSET ?")
+		args = append(args, b.offset)
+	}
+
+	return sb.String(), args
+}
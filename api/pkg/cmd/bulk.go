@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// maxVariables is SQLite's default SQLITE_MAX_VARIABLE_NUMBER limit
+const maxVariables = 999
+
+// BulkInsert inserts rows using multi-row INSERT statements of up to batchSize rows,
+// all inside a single transaction. The batch is reduced automatically so no statement
+// exceeds SQLite's bound variable limit. Returns the total number of rows inserted.
+func (d *LibSQLDatabase) BulkInsert(ctx context.Context, table string, columns []string, rows [][]any, batchSize int) (int64, error) {
+	if len(columns) == 0 {
+		return 0, fmt.Errorf("bulk insert into %s: no columns given", table)
+	}
+	if len(columns) > maxVariables {
+		return 0, fmt.Errorf("bulk insert into %s: %d columns exceeds the %d variable limit", table, len(columns), maxVariables)
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return 0, fmt.Errorf("bulk insert into %s: row %d has %d values, expected %d", table, i, len(row), len(columns))
+		}
+	}
+
+	if limit := maxVariables / len(columns); batchSize <= 0 || batchSize > limit {
+		batchSize = limit
+	}
+
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+	}
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES ", quoteIdent(table), strings.Join(quoted, ", "))
+	rowPlaceholder := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ") + ")"
+
+	var total int64
+	err := d.Transaction(ctx, func(tx *sql.Tx) error {
+		for offset := 0; offset < len(rows); offset += batchSize {
+			batch := rows[offset:min(offset+batchSize, len(rows))]
+
+			placeholders := make([]string, len(batch))
+			args := make([]any, 0, len(batch)*len(columns))
+			for i, row := range batch {
+				placeholders[i] = rowPlaceholder
+				args = append(args, row...)
+			}
+
+			query := prefix + strings.Join(placeholders, ", ")
+			queryCtx, cancel := d.queryContext(ctx)
+			start := time.Now()
+			result, err := tx.ExecContext(queryCtx, query, args...)
+			d.observeQuery(queryCtx, "bulk_insert", query, time.Since(start), err)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("failed to insert rows %d-%d: %w", offset, offset+len(batch)-1, err)
+			}
+
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to read rows affected: %w", err)
+			}
+			total += affected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("bulk insert into %s: %w", table, err)
+	}
+
+	return total, nil
+}
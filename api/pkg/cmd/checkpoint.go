@@ -0,0 +1,84 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Checkpoint runs PRAGMA wal_checkpoint(TRUNCATE) and returns SQLite's busy flag,
+// the number of frames in the WAL, and the number of frames checkpointed
+func (d *LibSQLDatabase) Checkpoint(ctx context.Context) (busy, logFrames, checkpointedFrames int, err error) {
+	return d.checkpoint(ctx, "TRUNCATE")
+}
+
+// checkpoint runs PRAGMA wal_checkpoint in the given mode
+func (d *LibSQLDatabase) checkpoint(ctx context.Context, mode string) (busy, logFrames, checkpointedFrames int, err error) {
+	if !isLocalFile(d.config.URL) {
+		return 0, 0, 0, fmt.Errorf("checkpoint is only supported for local file databases")
+	}
+
+	err = d.db.QueryRowContext(ctx, "PRAGMA wal_checkpoint("+mode+")").Scan(&busy, &logFrames, &checkpointedFrames)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to checkpoint WAL: %w", err)
+	}
+
+	return busy, logFrames, checkpointedFrames, nil
+}
+
+// checkpointLoop checkpoints the WAL on CheckpointInterval
+func (d *LibSQLDatabase) checkpointLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.config.CheckpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			busy, logFrames, checkpointed, err := d.Checkpoint(ctx)
+			if err != nil {
+				d.logger.Warn("scheduled WAL checkpoint failed", "error", err)
+				continue
+			}
+			d.logger.Debug("WAL checkpoint complete",
+				"busy", busy,
+				"log_frames", logFrames,
+				"checkpointed_frames", checkpointed,
+			)
+		}
+	}
+}
+
+// idleCheckpointLoop passively checkpoints the WAL once the database has been idle for
+// IdleCheckpointDelay, at most once per quiet period. PASSIVE never blocks writers, so
+// a burst that starts mid-checkpoint isn't held up.
+func (d *LibSQLDatabase) idleCheckpointLoop(ctx context.Context) {
+	ticker := time.NewTicker(max(d.config.IdleCheckpointDelay/2, 100*time.Millisecond))
+	defer ticker.Stop()
+
+	var checkpointedAt int64 // Activity timestamp the last checkpoint covered
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			last := d.lastActivity.Load()
+			if last == checkpointedAt || time.Since(time.Unix(0, last)) < d.config.IdleCheckpointDelay {
+				continue
+			}
+
+			busy, logFrames, checkpointed, err := d.checkpoint(ctx, "PASSIVE")
+			if err != nil {
+				d.logger.Warn("idle WAL checkpoint failed", "error", err)
+				continue
+			}
+			checkpointedAt = last
+			d.logger.Debug("idle WAL checkpoint complete",
+				"busy", busy,
+				"log_frames", logFrames,
+				"checkpointed_frames", checkpointed,
+			)
+		}
+	}
+}
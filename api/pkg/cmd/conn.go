@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// Conn acquires a dedicated connection from the pool, recording how long the caller
+// waited. It blocks until a connection is free or ctx is done. Callers must Close it.
+func (d *LibSQLDatabase) Conn(ctx context.Context) (*sql.Conn, error) {
+	start := time.Now()
+	conn, err := d.db.Conn(ctx)
+	waited := time.Since(start)
+
+	if d.metrics != nil {
+		d.metrics.connAcquireDuration.Observe(waited.Seconds())
+	}
+
+	if d.config.ConnAcquireWarnThreshold > 0 && waited > d.config.ConnAcquireWarnThreshold {
+		stats := d.db.Stats()
+		d.logger.Warn("slow connection acquisition, pool may be exhausted",
+			"waited", waited,
+			"in_use", stats.InUse,
+			"max_open_conns", stats.MaxOpenConnections,
+		)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	return conn, nil
+}
+
+// PinnedConn is a pooled connection reserved for connection-scoped work such as ATTACH,
+// temp tables and pragmas. It has the package's pragmas applied on acquisition, and
+// Release undoes attachments and pragma changes before handing it back to the pool.
+type PinnedConn struct {
+	*sql.Conn
+	db       *LibSQLDatabase
+	released bool
+}
+
+// AcquireConn reserves a connection and reapplies the connection pragmas (foreign keys,
+// busy timeout and cache tuning), in case an earlier caller changed them. Callers must
+// Release it.
+func (d *LibSQLDatabase) AcquireConn(ctx context.Context) (*PinnedConn, error) {
+	conn, err := d.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pinned := &PinnedConn{Conn: conn, db: d}
+	if err := pinned.applyPragmas(ctx); err != nil {
+		pinned.discard()
+		return nil, err
+	}
+	return pinned, nil
+}
+
+// Release detaches any attached databases, restores the connection pragmas and returns
+// the connection to the pool. A connection that can't be reset is closed instead, so
+// the next caller never inherits its state. Release is safe to call more than once.
+func (c *PinnedConn) Release() {
+	if c.released {
+		return
+	}
+	c.released = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.db.config.HealthTimeout)
+	defer cancel()
+
+	if err := c.reset(ctx); err != nil {
+		c.db.logger.Warn("failed to reset pinned connection, discarding it", "error", err)
+		c.discard()
+		return
+	}
+	c.Conn.Close()
+}
+
+// reset detaches attached databases and reapplies the connection pragmas
+func (c *PinnedConn) reset(ctx context.Context) error {
+	rows, err := c.QueryContext(ctx, "PRAGMA database_list")
+	if err != nil {
+		return fmt.Errorf("failed to list attached databases: %w", err)
+	}
+	var attached []string
+	for rows.Next() {
+		var seq int
+		var name, file string
+		if err := rows.Scan(&seq, &name, &file); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan attached database: %w", err)
+		}
+		if name != "main" && name != "temp" {
+			attached = append(attached, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to list attached databases: %w", err)
+	}
+
+	for _, name := range attached {
+		if _, err := c.ExecContext(ctx, "DETACH DATABASE "+quoteIdent(name)); err != nil {
+			return fmt.Errorf("failed to detach %s: %w", name, err)
+		}
+	}
+
+	return c.applyPragmas(ctx)
+}
+
+// applyPragmas runs the connector's per-connection pragmas on the pinned connection
+func (c *PinnedConn) applyPragmas(ctx context.Context) error {
+	for _, pragma := range c.db.connector.pragmas {
+		if _, err := c.ExecContext(ctx, pragma); err != nil {
+			return fmt.Errorf("failed to apply %q to pinned connection: %w", pragma, err)
+		}
+	}
+	return nil
+}
+
+// discard closes the connection rather than returning it to the pool
+func (c *PinnedConn) discard() {
+	// Returning ErrBadConn from Raw makes database/sql close the connection
+	c.Raw(func(any) error { return driver.ErrBadConn })
+	c.Conn.Close()
+}
@@ -0,0 +1,199 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/tursodatabase/go-libsql"
+)
+
+// pragmaConnector wraps the driver's connector so every new pooled connection gets the
+// per-connection pragmas. Setting them once at startup only reaches the connection that
+// happened to run them; connections opened later would silently miss them.
+type pragmaConnector struct {
+	driver.Connector
+	replica  *libsql.Connector      // Embedded replica connector, nil otherwise
+	key      atomic.Pointer[string] // Encryption key, applied before anything else touches the file
+	pragmas  []string
+	lifetime time.Duration // Base connection lifetime for jitter
+	jitter   time.Duration // Wraps connections with a randomized expiry when set
+
+	stmtTimeout time.Duration // Deadline for every statement on the connection
+	open        openResources // Unclosed rows and statements across the pool's connections
+
+	onFailover atomic.Pointer[func(error)] // Called when a connection hits a failover error
+	generation atomic.Int64                // Bumped to retire every connection opened before it
+}
+
+// newConnector builds a connector for connStr that applies the pragmas for cfg.
+// Embedded replicas get go-libsql's replica connector, which syncs a local file from
+// SyncURL; local files and memory databases use modernc.org/sqlite, and remote URLs
+// the libsql driver.
+func newConnector(cfg LibSQLConfig, connStr string) (*pragmaConnector, error) {
+	var base driver.Connector
+	var replica *libsql.Connector
+	if isEmbeddedReplica(cfg) {
+		var opts []libsql.Option
+		if cfg.AuthToken != "" {
+			opts = append(opts, libsql.WithAuthToken(cfg.AuthToken))
+		}
+		var err error
+		if replica, err = libsql.NewEmbeddedReplicaConnector(localPath(cfg.URL), cfg.SyncURL, opts...); err != nil {
+			return nil, fmt.Errorf("failed to open embedded replica: %w", err)
+		}
+		base = replica
+	} else {
+		driverName := "libsql"
+		if isLocalFile(cfg.URL) || isMemory(cfg.URL) {
+			driverName = "sqlite"
+		}
+
+		// sql.Open doesn't connect; it is only used to look up the registered driver
+		lookup, err := sql.Open(driverName, connStr)
+		if err != nil {
+			return nil, err
+		}
+		drv := lookup.Driver()
+		lookup.Close()
+
+		if dc, ok := drv.(driver.DriverContext); ok {
+			if base, err = dc.OpenConnector(connStr); err != nil {
+				return nil, err
+			}
+		} else {
+			base = dsnConnector{driver: drv, dsn: connStr}
+		}
+	}
+
+	c := &pragmaConnector{
+		Connector: base,
+		replica:   replica,
+		pragmas:   connectionPragmas(cfg),
+		lifetime:  cfg.ConnMaxLifetime,
+		jitter:    cfg.ConnMaxLifetimeJitter,
+
+		stmtTimeout: cfg.StatementTimeout,
+	}
+	// Remote servers encrypt at rest themselves
+	if cfg.EncryptionKey != "" && isLocalFile(cfg.URL) {
+		c.key.Store(&cfg.EncryptionKey)
+	}
+	return c, nil
+}
+
+// Connect opens a connection and applies the pragmas before handing it to the pool
+func (c *pragmaConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if key := c.key.Load(); key != nil {
+		if err := applyKey(ctx, conn, *key); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	for _, pragma := range c.pragmas {
+		if err := execConn(ctx, conn, pragma); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to apply %q to new connection: %w", pragma, err)
+		}
+	}
+
+	return newPooledConn(conn, c), nil
+}
+
+// Close releases the underlying connector when it holds resources, such as the embedded
+// replica's local database handle. sql.DB.Close calls it.
+func (c *pragmaConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// connectionPragmas lists the pragmas every connection needs for cfg
+func connectionPragmas(cfg LibSQLConfig) []string {
+	pragmas := []string{
+		"PRAGMA foreign_keys=ON", // Enable foreign key constraints
+	}
+
+	// Lock waits and page cache tuning only apply to local files; remote servers
+	// manage these themselves
+	if !isLocalFile(cfg.URL) {
+		return pragmas
+	}
+
+	pragmas = append(pragmas, fmt.Sprintf("PRAGMA busy_timeout=%d", cfg.BusyTimeout.Milliseconds()))
+
+	// A negative cache_size is read as KiB rather than pages
+	if cfg.CacheSizeKB > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA cache_size=-%d", cfg.CacheSizeKB))
+	}
+	if cfg.MmapSizeBytes > 0 {
+		pragmas = append(pragmas, fmt.Sprintf("PRAGMA mmap_size=%d", cfg.MmapSizeBytes))
+	}
+
+	// Zero freed pages so deleted rows don't linger in the file. Every delete and
+	// overwrite then rewrites the freed content, adding write I/O and WAL volume.
+	if cfg.SecureDelete {
+		pragmas = append(pragmas, "PRAGMA secure_delete=ON")
+	}
+
+	// Optimize WAL behavior
+	if cfg.JournalMode == "WAL" && !cfg.ReadOnly {
+		pragmas = append(pragmas,
+			"PRAGMA synchronous=NORMAL",      // Good balance of safety and speed
+			"PRAGMA wal_autocheckpoint=1000", // Checkpoint every 1000 pages
+		)
+	}
+
+	return pragmas
+}
+
+// execConn runs a statement directly on a driver connection
+func execConn(ctx context.Context, conn driver.Conn, query string) error {
+	if execer, ok := conn.(driver.ExecerContext); ok {
+		_, err := execer.ExecContext(ctx, query, nil)
+		if err != driver.ErrSkip {
+			return err
+		}
+	}
+
+	stmt, err := conn.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	if sc, ok := stmt.(driver.StmtExecContext); ok {
+		_, err = sc.ExecContext(ctx, nil)
+		return err
+	}
+	// Fallback for drivers without StmtExecContext
+	_, err = stmt.Exec(nil)
+	return err
+}
+
+// dsnConnector adapts a driver without DriverContext to driver.Connector
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+// Connect opens a connection with the stored DSN
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+// Driver returns the underlying driver
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
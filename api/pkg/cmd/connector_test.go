@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestForeignKeysEnforced(t *testing.T) {
+	tests := []struct {
+		name      string
+		enableWAL bool
+	}{
+		{name: "WAL", enableWAL: true},
+		{name: "rollback journal", enableWAL: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			cfg := testConfig(t)
+			cfg.EnableWAL = tt.enableWAL
+			db := openTestDB(t, cfg)
+
+			schema := []string{
+				"CREATE TABLE parents (id INTEGER PRIMARY KEY)",
+				"CREATE TABLE children (id INTEGER PRIMARY KEY, parent_id INTEGER NOT NULL REFERENCES parents(id))",
+			}
+			for _, stmt := range schema {
+				if _, err := db.Exec(ctx, "create_table", stmt); err != nil {
+					t.Fatalf("create schema: %v", err)
+				}
+			}
+
+			_, err := db.Exec(ctx, "insert", "INSERT INTO children (parent_id) VALUES (?)", 42)
+			if !errors.Is(err, ErrConstraint) {
+				t.Errorf("insert with a missing parent returned %v, want ErrConstraint", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExportCSV streams the results of query to w as CSV with a header row of column names.
+// NULLs are written as empty fields and timestamps as RFC 3339.
+func (d *LibSQLDatabase) ExportCSV(ctx context.Context, w io.Writer, query string, args ...any) error {
+	cw := csv.NewWriter(w)
+
+	err := d.Stream(ctx, func(rows *sql.Rows) error {
+		columns, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to read columns: %w", err)
+		}
+		if err := cw.Write(columns); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		record := make([]string, len(columns))
+
+		for rows.Next() {
+			if err := rows.Scan(dest...); err != nil {
+				return fmt.Errorf("failed to scan row: %w", err)
+			}
+			for i, v := range values {
+				record[i] = csvField(v)
+			}
+			if err := cw.Write(record); err != nil {
+				return fmt.Errorf("failed to write CSV row: %w", err)
+			}
+		}
+		return nil
+	}, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export CSV: %w", err)
+	}
+
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("failed to flush CSV: %w", err)
+	}
+	return nil
+}
+
+// ImportCSV inserts every record from r into table inside a single transaction. The
+// header row names the target columns. Empty fields are inserted as empty strings.
+// On failure nothing is imported and the error names the offending line.
+func (d *LibSQLDatabase) ImportCSV(ctx context.Context, table string, r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.ReuseRecord = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return fmt.Errorf("import into %s: missing CSV header", table)
+		}
+		return fmt.Errorf("import into %s: failed to read CSV header: %w", table, err)
+	}
+
+	quoted := make([]string, len(header))
+	for i, col := range header {
+		quoted[i] = quoteIdent(strings.TrimSpace(col))
+	}
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		quoteIdent(table),
+		strings.Join(quoted, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(header)), ", "),
+	)
+
+	var total int64
+	start := time.Now()
+	err = d.Transaction(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare insert: %w", err)
+		}
+		defer stmt.Close()
+
+		args := make([]any, len(header))
+		for {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				// csv.ParseError already carries the line number
+				return fmt.Errorf("failed to read CSV: %w", err)
+			}
+			line, _ := cr.FieldPos(0)
+
+			for i, field := range record {
+				args[i] = field
+			}
+			if _, err := stmt.ExecContext(ctx, args...); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			total++
+		}
+	})
+	d.observeQuery(ctx, "import_csv", query, time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("import into %s: %w", table, err)
+	}
+
+	d.logger.Info("CSV import complete", "table", table, "rows", total, "duration", time.Since(start))
+	return nil
+}
+
+// csvField formats a scanned value for CSV output
+func csvField(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []byte:
+		return string(v)
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -3,27 +3,74 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"log/slog"
+	"io/fs"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	_ "github.com/tursodatabase/libsql-client-go/libsql"
+	"go.opentelemetry.io/otel/trace"
 	_ "modernc.org/sqlite"
 )
 
 // LibSQLConfig holds configuration for libSQL database
 type LibSQLConfig struct {
-	URL             string        // libsql://[your-database].turso.io or file:path/to/db
-	AuthToken       string        // For Turso hosted instances
-	MaxOpenConns    int           // Maximum open connections
-	MaxIdleConns    int           // Maximum idle connections
-	ConnMaxLifetime time.Duration // Maximum connection lifetime
-	ConnMaxIdleTime time.Duration // Maximum idle time
-	EnableWAL       bool          // Enable Write-Ahead Logging for local files
-	EnableMetrics   bool          // Enable Prometheus metrics
-	MigrationPath   string        // Path to migration files
+	URL                      string                                                            // libsql://[your-database].turso.io or file:path/to/db
+	AuthToken                string                                                            // For Turso hosted instances
+	MaxOpenConns             int                                                               // Maximum open connections
+	MaxIdleConns             int                                                               // Maximum idle connections
+	ConnMaxLifetime          time.Duration                                                     // Maximum connection lifetime
+	ConnMaxIdleTime          time.Duration                                                     // Maximum idle time
+	EnableWAL                bool                                                              // Enable Write-Ahead Logging for local files
+	EnableMetrics            bool                                                              // Enable Prometheus metrics
+	MigrationPath            string                                                            // Path to migration files
+	SyncURL                  string                                                            // Remote primary for embedded replicas (requires a file: URL)
+	SyncInterval             time.Duration                                                     // Background sync cadence for embedded replicas (0 disables)
+	ReplicaURLs              []string                                                          // Optional read replicas; reads fall back to the primary when empty
+	MaxRetries               int                                                               // Retries for transient errors in TransactionWithRetry
+	RetryBackoff             time.Duration                                                     // Initial backoff between retries, doubled each attempt
+	StmtCacheSize            int                                                               // Maximum number of cached prepared statements
+	Registerer               prometheus.Registerer                                             // Metrics registry (nil uses prometheus.DefaultRegisterer)
+	InstanceLabel            string                                                            // Constant "database" label on all metrics (empty omits it)
+	CheckpointInterval       time.Duration                                                     // Periodic WAL checkpoint for local files (0 disables)
+	Tracer                   trace.Tracer                                                      // OpenTelemetry tracer for query spans (nil disables tracing)
+	RedactStatements         bool                                                              // Omit SQL text from span attributes
+	SlowQueryThreshold       time.Duration                                                     // Log queries slower than this (0 disables)
+	WarmupConns              int                                                               // Connections to open and ping before NewLibSQLDatabase returns
+	MaxReplicationLag        time.Duration                                                     // Readiness fails when the embedded replica is staler than this (0 disables)
+	BusyTimeout              time.Duration                                                     // How long local connections wait on locks before SQLITE_BUSY
+	MigrationFS              fs.FS                                                             // Migration source that takes precedence over MigrationPath (e.g. embed.FS via fs.Sub)
+	HealthTimeout            time.Duration                                                     // Timeout for Liveness/Readiness checks (default 1s)
+	ConnectTimeout           time.Duration                                                     // Timeout for the initial connection and ping (default 5s)
+	MaxQueryDuration         time.Duration                                                     // Deadline applied to queries issued through the helpers (0 disables)
+	ConnAcquireWarnThreshold time.Duration                                                     // Warn when Conn waits longer than this for a connection (0 disables)
+	JournalMode              string                                                            // WAL, DELETE, TRUNCATE, MEMORY or OFF for local files; empty follows EnableWAL
+	ReadOnly                 bool                                                              // Open local files with mode=ro and skip journal/pragma writes
+	HealthCheckInterval      time.Duration                                                     // Background health monitor cadence (0 disables)
+	OnStateChange            func(healthy bool)                                                // Called by the health monitor on debounced healthy/unhealthy transitions
+	BreakerThreshold         int                                                               // Consecutive failed health probes that open the circuit breaker (0 disables)
+	BreakerCooldown          time.Duration                                                     // How long the breaker stays open before half-opening (default 30s)
+	MaxTxDuration            time.Duration                                                     // Warn with the starting stack when a transaction runs longer than this (0 disables)
+	CacheSizeKB              int                                                               // Page cache size in KiB per connection (0 leaves the driver default)
+	MmapSizeBytes            int64                                                             // Memory-mapped I/O size; only helps local files, not remote Turso (0 leaves the driver default)
+	ConnMaxLifetimeJitter    time.Duration                                                     // Randomizes each connection's lifetime within ±jitter so connections don't all expire together
+	EncryptionKey            string                                                            // SQLCipher key applied to every connection to a local file; ignored for remote URLs
+	OptimizeOnClose          bool                                                              // Run PRAGMA optimize in Close (local files only)
+	OptimizeInterval         time.Duration                                                     // Run PRAGMA optimize periodically (local files only, 0 disables)
+	StrictPoolLimits         bool                                                              // Reject MaxIdleConns > MaxOpenConns in Validate instead of warning about the clamp
+	OnDataChanged            func()                                                            // Called (debounced) when another process writes the local database file or its WAL
+	WatchDebounce            time.Duration                                                     // Quiet period before OnDataChanged fires (default 100ms)
+	StatementTimeout         time.Duration                                                     // Interrupts any statement on a pooled connection that runs longer than this (0 disables)
+	OnMigration              func(version int, name, direction string, duration time.Duration) // Called after each migration is applied ("up") or rolled back ("down")
+	IdleCheckpointDelay      time.Duration                                                     // Passively checkpoint the WAL once no queries have run for this long (0 disables)
+	AutoTunePool             bool                                                              // Step the open connection limit between MinOpenConns and MaxOpenConns based on waits and idle connections
+	MinOpenConns             int                                                               // Lower bound for AutoTunePool (default 1)
+	ErrorClassifier          func(error) ErrorClass                                            // Decides which errors retries repeat and the circuit breaker counts (default DefaultErrorClassifier)
+	SecureDelete             bool                                                              // Overwrite deleted content with zeros on local files; costs extra writes on every delete
 }
 
 // DefaultLibSQLConfig returns production-ready defaults per CLAUDE.md
@@ -37,126 +84,378 @@ func DefaultLibSQLConfig() LibSQLConfig {
 		EnableWAL:       true,
 		EnableMetrics:   true,
 		MigrationPath:   "migrations",
+		MaxRetries:      3,
+		RetryBackoff:    50 * time.Millisecond,
+		StmtCacheSize:   100,
+		BusyTimeout:     5 * time.Second,
+		HealthTimeout:   1 * time.Second,
+		ConnectTimeout:  5 * time.Second,
 	}
 }
 
 // LibSQLDatabase manages the libSQL database connection
 type LibSQLDatabase struct {
-	db      *sql.DB
-	config  LibSQLConfig
-	logger  *slog.Logger
-	metrics *dbMetrics
-	mu      sync.RWMutex
+	db           *sql.DB
+	connector    *pragmaConnector // Opens the primary's connections
+	replicas     []*sql.DB
+	next         atomic.Uint64 // Round-robin cursor for replica selection
+	config       LibSQLConfig
+	logger       Logger
+	metrics      *dbMetrics
+	stmts        *stmtCache
+	results      *resultCache
+	breaker      *circuitBreaker
+	storage      storageGuard
+	queryTypes   *queryTypeRegistry // Allowlist for query_type metric labels
+	rowCounts    *rowCountCache     // Approximate per-table row counts
+	writeTxs     *writeTxTracker    // Open write transactions, to name lock holders
+	mu           sync.RWMutex
+	lastSync     atomic.Int64       // Unix nanos of the last successful replica sync
+	lastActivity atomic.Int64       // Unix nanos of the last query or transaction
+	queries      atomic.Uint64      // Queries observed, for the pool sizing advisor
+	poolTarget   atomic.Int64       // Open connection limit in force, moved by the auto-tuner
+	repinging    atomic.Bool        // Set while a post-failover ping is running
+	bgCtx        context.Context    // Ends when Close stops background goroutines
+	bgMu         sync.Mutex         // Orders starting a background goroutine against Close
+	cancel       context.CancelFunc // Stops background goroutines
+	wg           sync.WaitGroup     // Tracks background goroutines
 }
 
 // dbMetrics holds Prometheus metrics for database monitoring
 type dbMetrics struct {
-	openConnections prometheus.Gauge
-	idleConnections prometheus.Gauge
-	waitCount       prometheus.Gauge
-	waitDuration    prometheus.Gauge
-	queryDuration   *prometheus.HistogramVec
-	queryErrors     *prometheus.CounterVec
+	openConnections     prometheus.Gauge
+	idleConnections     prometheus.Gauge
+	waitCount           prometheus.Gauge
+	waitDuration        prometheus.Gauge
+	queryDuration       *prometheus.HistogramVec
+	queryErrors         *prometheus.CounterVec
+	stmtCache           *prometheus.CounterVec
+	slowQueries         *prometheus.CounterVec
+	replicationLag      prometheus.Gauge
+	queryCache          *prometheus.CounterVec
+	integrityProblems   *prometheus.CounterVec
+	queryTimeouts       *prometheus.CounterVec
+	connAcquireDuration prometheus.Histogram
+	breakerState        prometheus.Gauge
+	txDuration          *prometheus.HistogramVec
+	txRollbacks         *prometheus.CounterVec
+	rowsReturned        *prometheus.HistogramVec
+	rowsAffected        *prometheus.HistogramVec
+	storageDegraded     prometheus.Gauge
+	queryCancellations  *prometheus.CounterVec
+	openResources       *prometheus.GaugeVec
+	lockContention      *prometheus.CounterVec
 }
 
 // NewLibSQLDatabase creates a new libSQL database instance with production settings
-func NewLibSQLDatabase(cfg LibSQLConfig, logger *slog.Logger) (*LibSQLDatabase, error) {
+func NewLibSQLDatabase(cfg LibSQLConfig, logger Logger) (*LibSQLDatabase, error) {
+	logger = resolveLogger(logger)
+
 	// Validate configuration
-	if cfg.URL == "" {
-		return nil, fmt.Errorf("database URL is required")
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
 	}
-
-	// Build connection string
-	connStr := cfg.URL
-	if cfg.AuthToken != "" {
-		connStr = fmt.Sprintf("%s?authToken=%s", cfg.URL, cfg.AuthToken)
+	if cfg.idleExceedsOpen() {
+		logger.Warn("MaxIdleConns exceeds MaxOpenConns and will be clamped",
+			"max_idle_conns", cfg.MaxIdleConns,
+			"max_open_conns", cfg.MaxOpenConns,
+			"effective_idle_conns", cfg.MaxOpenConns,
+		)
 	}
 
-	// Open database connection
-	db, err := sql.Open("libsql", connStr)
+	// Resolve the journal mode, treating EnableWAL as shorthand for WAL
+	mode, err := resolveJournalMode(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
 	}
+	cfg.JournalMode = mode
 
-	// Configure connection pool per CLAUDE.md guidelines
-	db.SetMaxOpenConns(cfg.MaxOpenConns)
-	db.SetMaxIdleConns(cfg.MaxIdleConns)
-	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
-	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	// Apply defaults for settings that were left unset
+	if cfg.StmtCacheSize <= 0 {
+		cfg.StmtCacheSize = 100
+	}
+	if cfg.BusyTimeout == 0 {
+		cfg.BusyTimeout = 5 * time.Second
+	}
+	if cfg.HealthTimeout <= 0 {
+		cfg.HealthTimeout = 1 * time.Second
+	}
+	if cfg.ConnectTimeout <= 0 {
+		cfg.ConnectTimeout = 5 * time.Second
+	}
+	if cfg.ConnMaxLifetimeJitter >= cfg.ConnMaxLifetime && cfg.ConnMaxLifetime > 0 {
+		// Keep every jittered lifetime positive
+		cfg.ConnMaxLifetimeJitter = cfg.ConnMaxLifetime / 2
+	}
+	if cfg.OnDataChanged != nil && cfg.WatchDebounce <= 0 {
+		cfg.WatchDebounce = 100 * time.Millisecond
+	}
+	if isMemory(cfg.URL) {
+		// Each connection to a memory database sees its own private copy (or, with a
+		// shared cache, the data vanishes once the last connection closes), so pin the
+		// pool to a single connection that is never recycled
+		cfg.MaxOpenConns = 1
+		cfg.MaxIdleConns = 1
+		cfg.ConnMaxLifetime = 0
+		cfg.ConnMaxIdleTime = 0
+		cfg.ConnMaxLifetimeJitter = 0
+		cfg.WarmupConns = 0
+		cfg.AutoTunePool = false
+	}
+	if cfg.AutoTunePool && cfg.MinOpenConns <= 0 {
+		cfg.MinOpenConns = 1
+	}
+	if cfg.BreakerThreshold > 0 {
+		// The breaker is driven by the health monitor, so it needs probes
+		if cfg.HealthCheckInterval <= 0 {
+			cfg.HealthCheckInterval = 5 * time.Second
+		}
+		if cfg.BreakerCooldown <= 0 {
+			cfg.BreakerCooldown = 30 * time.Second
+		}
+	}
+
+	// Create the data directory, failing clearly on a read-only volume
+	// instead of with a cryptic driver error
+	if isLocalFile(cfg.URL) && !cfg.ReadOnly {
+		if err := ensureDataDir(localPath(cfg.URL)); err != nil {
+			return nil, err
+		}
+		if err := checkWritable(localPath(cfg.URL)); err != nil {
+			return nil, err
+		}
+	}
 
 	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
 	defer cancel()
 
-	if err := db.PingContext(ctx); err != nil {
+	// Open primary connection pool
+	connStr, err := buildConnStr(cfg)
+	if err != nil {
+		return nil, err
+	}
+	db, connector, err := openPool(ctx, cfg, connStr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Open read replica pools
+	replicas, err := openReplicas(ctx, cfg)
+	if err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+		return nil, err
+	}
+
+	// Pre-open connections so the first requests don't pay connection setup
+	if cfg.WarmupConns > 0 {
+		if err := warmup(ctx, db, cfg, logger); err != nil {
+			for _, replica := range replicas {
+				replica.Close()
+			}
+			db.Close()
+			return nil, err
+		}
 	}
 
 	ldb := &LibSQLDatabase{
-		db:     db,
-		config: cfg,
-		logger: logger,
+		db:         db,
+		connector:  connector,
+		replicas:   replicas,
+		config:     cfg,
+		logger:     logger,
+		stmts:      newStmtCache(cfg.StmtCacheSize),
+		results:    newResultCache(),
+		breaker:    newCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		queryTypes: newQueryTypeRegistry(),
+		rowCounts:  newRowCountCache(),
+		writeTxs:   newWriteTxTracker(),
+	}
+	// Evict connections to a primary that failed over (remote databases only)
+	if !isLocalFile(cfg.URL) {
+		onFailover := ldb.handleFailover
+		connector.onFailover.Store(&onFailover)
 	}
 
-	// Enable WAL mode for better concurrency (local files only)
-	if cfg.EnableWAL && isLocalFile(cfg.URL) {
-		if err := ldb.enableWAL(ctx); err != nil {
-			logger.Warn("failed to enable WAL mode", "error", err)
+	// Embedded replicas sync when opened, so count the replica as fresh from here
+	ldb.lastSync.Store(time.Now().UnixNano())
+
+	// Set the journal mode; WAL gives better concurrency (local files only)
+	if cfg.JournalMode != "" && isLocalFile(cfg.URL) && !cfg.ReadOnly {
+		if err := ldb.setJournalMode(ctx, cfg.JournalMode); err != nil {
+			logger.Warn("failed to set journal mode", "journal_mode", cfg.JournalMode, "error", err)
 		}
 	}
 
 	// Setup metrics if enabled
 	if cfg.EnableMetrics {
-		ldb.setupMetrics()
+		if err := ldb.setupMetrics(); err != nil {
+			logger.Warn("failed to register metrics, continuing without them", "error", err)
+		}
 	}
 
+	// Background goroutines run until Close cancels this context
+	bgCtx, bgCancel := context.WithCancel(context.Background())
+	ldb.bgCtx, ldb.cancel = bgCtx, bgCancel
+
 	// Start metrics collector
-	go ldb.collectMetrics(context.Background())
+	ldb.goBackground(bgCtx, ldb.collectMetrics)
+
+	// Truncate the WAL periodically (local files only)
+	if isLocalFile(cfg.URL) && !cfg.ReadOnly && cfg.CheckpointInterval > 0 {
+		ldb.goBackground(bgCtx, ldb.checkpointLoop)
+	}
+
+	// Trim the WAL during quiet periods (local WAL databases only)
+	if isLocalFile(cfg.URL) && !cfg.ReadOnly && cfg.JournalMode == "WAL" && cfg.IdleCheckpointDelay > 0 {
+		ldb.goBackground(bgCtx, ldb.idleCheckpointLoop)
+	}
+
+	// Resize the pool with demand (opt-in)
+	ldb.poolTarget.Store(int64(cfg.MaxOpenConns))
+	if cfg.AutoTunePool {
+		ldb.goBackground(bgCtx, ldb.poolTuneLoop)
+	}
+
+	// Keep planner statistics fresh (local files only)
+	if ldb.canOptimize() && cfg.OptimizeInterval > 0 {
+		ldb.goBackground(bgCtx, ldb.optimizeLoop)
+	}
+
+	// Close idle connections once their jittered lifetime runs out
+	if cfg.ConnMaxLifetimeJitter > 0 && cfg.ConnMaxLifetime > 0 {
+		ldb.goBackground(bgCtx, ldb.sweepAgedConns)
+	}
+
+	// Notify about writes from other processes sharing the file (local files only)
+	if cfg.OnDataChanged != nil && isLocalFile(cfg.URL) {
+		if watcher, err := newFileWatcher(localPath(cfg.URL)); err != nil {
+			logger.Warn("failed to watch database file, OnDataChanged disabled", "error", err)
+		} else {
+			ldb.goBackground(bgCtx, func(ctx context.Context) { ldb.watchDataChanges(ctx, watcher) })
+		}
+	}
+
+	// Watch connectivity independently of the metrics collector
+	if cfg.HealthCheckInterval > 0 {
+		ldb.goBackground(bgCtx, ldb.healthMonitor)
+	}
+
+	// Keep embedded replicas in sync with the remote primary
+	if isEmbeddedReplica(cfg) && cfg.SyncInterval > 0 {
+		ldb.goBackground(bgCtx, ldb.syncLoop)
+	}
 
 	logger.Info("libSQL database initialized",
-		"url", cfg.URL,
+		"url", redactDSN(cfg.URL),
 		"max_open_conns", cfg.MaxOpenConns,
-		"wal_enabled", cfg.EnableWAL,
+		"journal_mode", cfg.JournalMode,
+		"embedded_replica", isEmbeddedReplica(cfg),
+		"in_memory", isMemory(cfg.URL),
+		"read_replicas", len(replicas),
 	)
+	if cfg.StatementTimeout > 0 {
+		// Neither driver exposes a progress handler, so this is the only path
+		logger.Info("statement timeout enforced through context deadlines on pooled connections",
+			"statement_timeout", cfg.StatementTimeout,
+		)
+	}
 
 	return ldb, nil
 }
 
+// openPool opens a connection pool, applies pool settings, and verifies connectivity
+func openPool(ctx context.Context, cfg LibSQLConfig, connStr string) (*sql.DB, *pragmaConnector, error) {
+	connector, err := newConnector(cfg, connStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open database: %w", redactError(err, cfg, connStr))
+	}
+	db := sql.OpenDB(connector)
+
+	// Configure connection pool per CLAUDE.md guidelines
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	if cfg.ConnMaxLifetimeJitter > 0 && cfg.ConnMaxLifetime > 0 {
+		// The connector enforces the jittered lifetime; the pool limit is only a backstop
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime + cfg.ConnMaxLifetimeJitter)
+	}
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		if errors.Is(err, ErrEncryptionUnsupported) {
+			// The connection worked; the build just can't encrypt
+			return nil, nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		return nil, nil, fmt.Errorf("%w: failed to ping database: %w", ErrConnFailed, redactError(err, cfg, connStr))
+	}
+
+	return db, connector, nil
+}
+
+// goBackground runs fn in a goroutine tracked by Close
+func (d *LibSQLDatabase) goBackground(ctx context.Context, fn func(context.Context)) {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		fn(ctx)
+	}()
+}
+
 // DB returns the underlying sql.DB for direct access
 func (d *LibSQLDatabase) DB() *sql.DB {
 	return d.db
 }
 
+// Config returns the effective configuration after defaults were applied. Secrets
+// (AuthToken, EncryptionKey) are replaced with "REDACTED" when set.
+func (d *LibSQLDatabase) Config() LibSQLConfig {
+	cfg := d.config
+	if cfg.AuthToken != "" {
+		cfg.AuthToken = "REDACTED"
+	}
+	if cfg.EncryptionKey != "" {
+		cfg.EncryptionKey = "REDACTED"
+	}
+	cfg.ReplicaURLs = slices.Clone(cfg.ReplicaURLs)
+	return cfg
+}
+
 // Close gracefully closes the database connection
 func (d *LibSQLDatabase) Close() error {
 	d.logger.Info("closing database connection")
-	return d.db.Close()
-}
-
-// Health checks database connectivity and returns status
-func (d *LibSQLDatabase) Health(ctx context.Context) error {
-	// Set a timeout for health check
-	ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
-	defer cancel()
 
-	// Ping the database
-	if err := d.db.PingContext(ctx); err != nil {
-		return fmt.Errorf("database ping failed: %w", err)
+	// Stop background goroutines before tearing down the pools they use
+	d.bgMu.Lock()
+	d.cancel()
+	d.bgMu.Unlock()
+	d.wg.Wait()
+
+	// Refresh planner statistics while the connections still hold their usage history
+	if d.config.OptimizeOnClose && d.canOptimize() {
+		ctx, cancel := context.WithTimeout(context.Background(), optimizeTimeout)
+		if err := d.runMaintenance(ctx, "PRAGMA optimize"); err != nil {
+			d.logger.Warn("failed to optimize database on close", "error", err)
+		}
+		cancel()
 	}
 
-	// Run a simple query to verify functionality
-	var result int
-	err := d.db.QueryRowContext(ctx, "SELECT 1").Scan(&result)
-	if err != nil {
-		return fmt.Errorf("health query failed: %w", err)
-	}
+	d.stmts.closeAll()
 
-	if result != 1 {
-		return fmt.Errorf("unexpected health check result: %d", result)
+	for _, replica := range d.replicas {
+		if err := replica.Close(); err != nil {
+			d.logger.Warn("failed to close read replica", "error", err)
+		}
 	}
 
-	return nil
+	return d.db.Close()
+}
+
+// Health checks database connectivity and returns status.
+// It is an alias for Readiness, kept for compatibility.
+func (d *LibSQLDatabase) Health(ctx context.Context) error {
+	return d.Readiness(ctx)
 }
 
 // Stats returns database statistics for monitoring
@@ -166,139 +465,535 @@ func (d *LibSQLDatabase) Stats() sql.DBStats {
 
 // Transaction executes a function within a database transaction
 func (d *LibSQLDatabase) Transaction(ctx context.Context, fn func(*sql.Tx) error) error {
-	tx, err := d.db.BeginTx(ctx, nil)
+	return d.TransactionWithOptions(ctx, nil, fn)
+}
+
+// TransactionWithOptions executes a function within a transaction started with opts.
+// Read-only transactions additionally enable PRAGMA query_only for their duration.
+func (d *LibSQLDatabase) TransactionWithOptions(ctx context.Context, opts *sql.TxOptions, fn func(*sql.Tx) error) (err error) {
+	ctx, span := d.startSpan(ctx, "db.transaction", "")
+	defer func() { endSpan(span, err) }()
+
+	if err := d.breaker.allow(); err != nil {
+		return err
+	}
+
+	if opts == nil || !opts.ReadOnly {
+		if err := d.allowWrite(); err != nil {
+			return err
+		}
+		defer func() { d.recordWrite(err) }()
+	}
+
+	if opts != nil {
+		switch opts.Isolation {
+		case sql.LevelDefault, sql.LevelSerializable:
+			// SQLite transactions are always serializable
+		default:
+			return fmt.Errorf("unsupported isolation level %s: SQLite only supports serializable", opts.Isolation)
+		}
+
+		if opts.ReadOnly {
+			fn = d.queryOnly(ctx, fn)
+		}
+	}
+
+	tx, err := d.db.BeginTx(ctx, opts)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	start := time.Now()
+	origin := d.txOrigin()
+
+	var writeID uint64 // Zero for read-only transactions
+	if opts == nil || !opts.ReadOnly {
+		writeID = d.writeTxs.begin(txCaller())
+		defer d.writeTxs.end(writeID)
+	}
 
 	defer func() {
 		if p := recover(); p != nil {
 			tx.Rollback()
+			d.observeTransaction(start, origin, "panic")
 			panic(p) // Re-panic after rollback
 		}
 	}()
 
 	if err := fn(tx); err != nil {
+		if IsBusy(err) {
+			d.observeLockContention(ctx, "transaction", "", writeID, err)
+		}
 		if rbErr := tx.Rollback(); rbErr != nil {
-			d.logger.Error("failed to rollback transaction", "error", rbErr)
+			d.logger.Error("failed to rollback transaction", append([]any{"error", rbErr}, logTags(ctx)...)...)
 		}
+		d.observeTransaction(start, origin, "error")
 		return err
 	}
 
 	if err := tx.Commit(); err != nil {
+		if IsBusy(err) {
+			d.observeLockContention(ctx, "commit", "COMMIT", writeID, err)
+		}
+		d.observeTransaction(start, origin, "commit")
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	d.observeTransaction(start, origin, "")
+	if writeID != 0 {
+		markWrite(ctx)
+	}
 	return nil
 }
 
-// enableWAL enables Write-Ahead Logging for better concurrency
-func (d *LibSQLDatabase) enableWAL(ctx context.Context) error {
-	_, err := d.db.ExecContext(ctx, "PRAGMA journal_mode=WAL")
-	if err != nil {
-		return fmt.Errorf("failed to enable WAL: %w", err)
+// observeTransaction records how long a transaction ran and warns past MaxTxDuration. rollbackCause is empty for
+// committed transactions, otherwise "error", "panic" or "commit" (a failed commit).
+func (d *LibSQLDatabase) observeTransaction(start time.Time, origin, rollbackCause string) {
+	d.lastActivity.Store(time.Now().UnixNano())
+
+	duration := time.Since(start)
+	if d.config.MaxTxDuration > 0 && duration > d.config.MaxTxDuration {
+		d.logger.Warn("transaction held open too long",
+			"duration", duration,
+			"threshold", d.config.MaxTxDuration,
+			"rolled_back", rollbackCause != "",
+			"started_at", origin,
+		)
+	}
+
+	if d.metrics == nil {
+		return
+	}
+
+	outcome := "commit"
+	if rollbackCause != "" {
+		outcome = "rollback"
+		d.metrics.txRollbacks.WithLabelValues(rollbackCause).Inc()
 	}
+	d.metrics.txDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
+
+// queryOnly wraps fn so it runs with PRAGMA query_only enabled on the transaction's connection
+func (d *LibSQLDatabase) queryOnly(ctx context.Context, fn func(*sql.Tx) error) func(*sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "PRAGMA query_only=ON"); err != nil {
+			return fmt.Errorf("failed to enable query_only: %w", err)
+		}
 
-	// Optimize WAL behavior
-	pragmas := []string{
-		"PRAGMA synchronous=NORMAL",      // Good balance of safety and speed
-		"PRAGMA wal_autocheckpoint=1000", // Checkpoint every 1000 pages
-		"PRAGMA busy_timeout=5000",       // Wait up to 5 seconds for locks
-		"PRAGMA foreign_keys=ON",         // Enable foreign key constraints
+		// Pragmas outlive the transaction, so always restore the pooled connection
+		defer func() {
+			if _, err := tx.ExecContext(context.WithoutCancel(ctx), "PRAGMA query_only=OFF"); err != nil {
+				d.logger.Error("failed to disable query_only", "error", err)
+			}
+		}()
+
+		return fn(tx)
 	}
+}
+
+// journalModes lists the journal modes accepted in JournalMode
+var journalModes = map[string]bool{
+	"WAL":      true,
+	"DELETE":   true,
+	"TRUNCATE": true,
+	"MEMORY":   true,
+	"OFF":      true,
+}
 
-	for _, pragma := range pragmas {
-		if _, err := d.db.ExecContext(ctx, pragma); err != nil {
-			d.logger.Warn("failed to set pragma", "pragma", pragma, "error", err)
+// resolveJournalMode validates JournalMode, falling back to WAL when EnableWAL is set
+func resolveJournalMode(cfg LibSQLConfig) (string, error) {
+	mode := strings.ToUpper(cfg.JournalMode)
+	if mode == "" {
+		if cfg.EnableWAL {
+			return "WAL", nil
 		}
+		return "", nil
+	}
+
+	if !journalModes[mode] {
+		return "", fmt.Errorf("unknown journal mode %q: expected WAL, DELETE, TRUNCATE, MEMORY or OFF", cfg.JournalMode)
+	}
+	return mode, nil
+}
+
+// setJournalMode sets the journal mode. It is stored in the database file, so unlike
+// the per-connection pragmas it only needs setting once.
+func (d *LibSQLDatabase) setJournalMode(ctx context.Context, mode string) error {
+	var got string
+	if err := d.db.QueryRowContext(ctx, "PRAGMA journal_mode="+mode).Scan(&got); err != nil {
+		return fmt.Errorf("failed to set journal mode: %w", err)
+	}
+
+	// SQLite reports the resulting mode, which differs if the request was refused
+	if !strings.EqualFold(got, mode) {
+		return fmt.Errorf("journal mode is %s, requested %s", got, mode)
 	}
 
 	return nil
 }
 
 // setupMetrics initializes Prometheus metrics
-func (d *LibSQLDatabase) setupMetrics() {
-	d.metrics = &dbMetrics{
+func (d *LibSQLDatabase) setupMetrics() error {
+	// Distinguish multiple instances in the same process
+	var labels prometheus.Labels
+	if d.config.InstanceLabel != "" {
+		labels = prometheus.Labels{"database": d.config.InstanceLabel}
+	}
+
+	m := &dbMetrics{
 		openConnections: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "database_open_connections",
-			Help: "Number of open database connections",
+			Name:        "database_open_connections",
+			Help:        "Number of open database connections",
+			ConstLabels: labels,
 		}),
 		idleConnections: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "database_idle_connections",
-			Help: "Number of idle database connections",
+			Name:        "database_idle_connections",
+			Help:        "Number of idle database connections",
+			ConstLabels: labels,
 		}),
 		waitCount: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "database_wait_count",
-			Help: "Number of connections waiting",
+			Name:        "database_wait_count",
+			Help:        "Number of connections waiting",
+			ConstLabels: labels,
 		}),
 		waitDuration: prometheus.NewGauge(prometheus.GaugeOpts{
-			Name: "database_wait_duration_seconds",
-			Help: "Time spent waiting for connections",
+			Name:        "database_wait_duration_seconds",
+			Help:        "Time spent waiting for connections",
+			ConstLabels: labels,
 		}),
 		queryDuration: prometheus.NewHistogramVec(
 			prometheus.HistogramOpts{
-				Name:    "database_query_duration_seconds",
-				Help:    "Database query duration in seconds",
-				Buckets: prometheus.DefBuckets,
+				Name:        "database_query_duration_seconds",
+				Help:        "Database query duration in seconds",
+				ConstLabels: labels,
+				Buckets:     prometheus.DefBuckets,
 			},
 			[]string{"query_type"},
 		),
 		queryErrors: prometheus.NewCounterVec(
 			prometheus.CounterOpts{
-				Name: "database_query_errors_total",
-				Help: "Total number of database query errors",
+				Name:        "database_query_errors_total",
+				Help:        "Total number of database query errors",
+				ConstLabels: labels,
+			},
+			[]string{"query_type"},
+		),
+		stmtCache: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_stmt_cache_requests_total",
+				Help:        "Prepared statement cache lookups by result",
+				ConstLabels: labels,
+			},
+			[]string{"result"},
+		),
+		slowQueries: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_slow_queries_total",
+				Help:        "Queries exceeding the slow query threshold",
+				ConstLabels: labels,
+			},
+			[]string{"query_type"},
+		),
+		replicationLag: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "database_replication_lag_seconds",
+			Help:        "Time since the embedded replica last synced successfully",
+			ConstLabels: labels,
+		}),
+		queryCache: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_query_cache_requests_total",
+				Help:        "Query result cache lookups by result",
+				ConstLabels: labels,
+			},
+			[]string{"result"},
+		),
+		integrityProblems: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_integrity_problems_total",
+				Help:        "Problems reported by integrity checks",
+				ConstLabels: labels,
+			},
+			[]string{"check"},
+		),
+		queryTimeouts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_query_timeouts_total",
+				Help:        "Queries cancelled by a deadline",
+				ConstLabels: labels,
+			},
+			[]string{"query_type"},
+		),
+		connAcquireDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:        "database_conn_acquire_duration_seconds",
+			Help:        "Time spent waiting to acquire a connection from the pool",
+			ConstLabels: labels,
+			Buckets:     prometheus.ExponentialBuckets(0.0005, 2, 14),
+		}),
+		breakerState: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "database_circuit_breaker_state",
+			Help:        "Circuit breaker state (0 closed, 1 half-open, 2 open)",
+			ConstLabels: labels,
+		}),
+		txDuration: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "database_transaction_duration_seconds",
+				Help:        "Time transactions held a connection, by outcome",
+				ConstLabels: labels,
+				Buckets:     prometheus.ExponentialBuckets(0.005, 2, 14),
+			},
+			[]string{"outcome"},
+		),
+		txRollbacks: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_transaction_rollbacks_total",
+				Help:        "Total number of rolled back transactions, by cause",
+				ConstLabels: labels,
+			},
+			[]string{"cause"},
+		),
+		rowsReturned: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "database_rows_returned",
+				Help:        "Rows returned by reads, by query type",
+				ConstLabels: labels,
+				Buckets:     prometheus.ExponentialBuckets(1, 4, 10),
+			},
+			[]string{"query_type"},
+		),
+		rowsAffected: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:        "database_rows_affected",
+				Help:        "Rows affected by writes, by query type",
+				ConstLabels: labels,
+				Buckets:     prometheus.ExponentialBuckets(1, 4, 10),
+			},
+			[]string{"query_type"},
+		),
+		storageDegraded: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "database_storage_degraded",
+			Help:        "1 while writes are refused because storage is full",
+			ConstLabels: labels,
+		}),
+		queryCancellations: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_query_cancelled_total",
+				Help:        "Total number of queries abandoned because their context was cancelled or timed out",
+				ConstLabels: labels,
+			},
+			[]string{"query_type", "reason"},
+		),
+		openResources: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name:        "database_open_resources",
+				Help:        "Result sets and prepared statements currently open on the primary, by kind",
+				ConstLabels: labels,
+			},
+			[]string{"kind"},
+		),
+		lockContention: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name:        "database_lock_contention_total",
+				Help:        "Total number of statements that failed waiting for a database lock, by query type",
+				ConstLabels: labels,
 			},
 			[]string{"query_type"},
 		),
 	}
 
-	// Register metrics
-	prometheus.MustRegister(
-		d.metrics.openConnections,
-		d.metrics.idleConnections,
-		d.metrics.waitCount,
-		d.metrics.waitDuration,
-		d.metrics.queryDuration,
-		d.metrics.queryErrors,
-	)
+	// Register metrics, reusing collectors already registered by another instance
+	reg := d.config.Registerer
+	if reg == nil {
+		reg = prometheus.DefaultRegisterer
+	}
+
+	r := &metricsRegistrar{reg: reg}
+	m.openConnections = register(r, m.openConnections)
+	m.idleConnections = register(r, m.idleConnections)
+	m.waitCount = register(r, m.waitCount)
+	m.waitDuration = register(r, m.waitDuration)
+	m.queryDuration = register(r, m.queryDuration)
+	m.queryErrors = register(r, m.queryErrors)
+	m.stmtCache = register(r, m.stmtCache)
+	m.slowQueries = register(r, m.slowQueries)
+	m.replicationLag = register(r, m.replicationLag)
+	m.queryCache = register(r, m.queryCache)
+	m.integrityProblems = register(r, m.integrityProblems)
+	m.queryTimeouts = register(r, m.queryTimeouts)
+	m.connAcquireDuration = register(r, m.connAcquireDuration)
+	m.breakerState = register(r, m.breakerState)
+	m.txDuration = register(r, m.txDuration)
+	m.txRollbacks = register(r, m.txRollbacks)
+	m.rowsReturned = register(r, m.rowsReturned)
+	m.rowsAffected = register(r, m.rowsAffected)
+	m.storageDegraded = register(r, m.storageDegraded)
+	m.queryCancellations = register(r, m.queryCancellations)
+	m.openResources = register(r, m.openResources)
+	m.lockContention = register(r, m.lockContention)
+	if err := errors.Join(r.errs...); err != nil {
+		return err
+	}
+
+	d.metrics = m
+	return nil
 }
 
-// collectMetrics periodically collects database metrics
-func (d *LibSQLDatabase) collectMetrics(ctx context.Context) {
-	if d.metrics == nil {
-		return
+// metricsRegistrar registers collectors and accumulates registration errors
+type metricsRegistrar struct {
+	reg  prometheus.Registerer
+	errs []error
+}
+
+// register registers c, returning the existing collector if an identical one is already registered
+func register[T prometheus.Collector](r *metricsRegistrar, c T) T {
+	err := r.reg.Register(c)
+	if err == nil {
+		return c
 	}
 
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		if existing, ok := already.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+
+	r.errs = append(r.errs, err)
+	return c
+}
+
+// collectMetrics periodically collects database metrics, checks for leaked rows and
+// advises on pool size
+func (d *LibSQLDatabase) collectMetrics(ctx context.Context) {
 	ticker := time.NewTicker(10 * time.Second)
 	defer ticker.Stop()
 
+	var leaking bool // Whether open rows were over the limit last tick
+	var advisor poolAdvisor
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
+			leaking = d.checkOpenRows(leaking)
+			d.advisePoolSize(&advisor)
+			if d.metrics == nil {
+				continue
+			}
+
+			d.metrics.openResources.WithLabelValues("rows").Set(float64(d.OpenRows()))
+			d.metrics.openResources.WithLabelValues("statements").Set(float64(d.OpenStatements()))
 			stats := d.db.Stats()
 			d.metrics.openConnections.Set(float64(stats.OpenConnections))
 			d.metrics.idleConnections.Set(float64(stats.Idle))
 			d.metrics.waitCount.Set(float64(stats.WaitCount))
 			d.metrics.waitDuration.Set(stats.WaitDuration.Seconds())
+			if lag, err := d.ReplicationLag(ctx); err == nil {
+				d.metrics.replicationLag.Set(lag.Seconds())
+			}
 		}
 	}
 }
 
 // ObserveQuery records query metrics
 func (d *LibSQLDatabase) ObserveQuery(queryType string, duration time.Duration, err error) {
+	d.observeQuery(context.Background(), queryType, "", duration, err)
+}
+
+// observeQuery records query metrics and logs queries exceeding SlowQueryThreshold
+func (d *LibSQLDatabase) observeQuery(ctx context.Context, queryType, query string, duration time.Duration, err error) {
+	d.lastActivity.Store(time.Now().UnixNano())
+	d.queries.Add(1)
+
+	slow := d.config.SlowQueryThreshold > 0 && duration > d.config.SlowQueryThreshold
+	if slow {
+		d.logger.Warn("slow query", append([]any{
+			"query_type", queryType,
+			"duration", duration,
+			"statement", truncateStatement(query),
+		}, logTags(ctx)...)...)
+	}
+	if IsBusy(err) {
+		d.observeLockContention(ctx, queryType, query, 0, err)
+	}
+	if err != nil && !errors.Is(err, ErrNoRows) {
+		d.logger.Debug("query failed", append([]any{
+			"query_type", queryType,
+			"error", err,
+		}, logTags(ctx)...)...)
+	}
+
 	if d.metrics == nil {
 		return
 	}
 
+	queryType = d.queryLabel(queryType)
 	d.metrics.queryDuration.WithLabelValues(queryType).Observe(duration.Seconds())
-	if err != nil {
+	// A cancelled caller isn't a database failure, so keep it out of the error rate
+	if reason := cancellationReason(ctx, err); reason != "" {
+		d.metrics.queryCancellations.WithLabelValues(queryType, reason).Inc()
+	} else if err != nil {
 		d.metrics.queryErrors.WithLabelValues(queryType).Inc()
 	}
+	if slow {
+		d.metrics.slowQueries.WithLabelValues(queryType).Inc()
+	}
+	if err != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+		d.metrics.queryTimeouts.WithLabelValues(queryType).Inc()
+	}
+}
+
+// cancellationReason returns "canceled" or "deadline_exceeded" when err came from the
+// query's context ending, and "" otherwise
+func cancellationReason(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(ctx.Err(), context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "deadline_exceeded"
+	}
+	return ""
+}
+
+// truncateStatement shortens SQL text for logging
+func truncateStatement(query string) string {
+	const maxLen = 200
+	if len(query) <= maxLen {
+		return query
+	}
+	return query[:maxLen] + "..."
 }
 
-// isLocalFile checks if the URL is a local file
+// isLocalFile checks if the URL is a local file on disk
 func isLocalFile(url string) bool {
-	return len(url) > 5 && url[:5] == "file:"
+	return len(url) > 5 && url[:5] == "file:" && !isMemory(url)
+}
+
+// isMemory reports whether url names an in-memory database, which never touches disk
+func isMemory(url string) bool {
+	if url == ":memory:" || strings.HasPrefix(url, "file::memory:") {
+		return true
+	}
+	_, query, _ := strings.Cut(url, "?")
+	for _, param := range strings.Split(query, "&") {
+		if param == "mode=memory" {
+			return true
+		}
+	}
+	return false
+}
+
+// localPath extracts the filesystem path from a file: URL, dropping any query string
+func localPath(url string) string {
+	path := strings.TrimPrefix(url, "file:")
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return path
+}
+
+// quoteIdent quotes an SQL identifier, escaping embedded double quotes
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
 }
@@ -0,0 +1,276 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// testConfig returns a config for a fresh database file in a temporary directory
+func testConfig(t *testing.T) LibSQLConfig {
+	t.Helper()
+	cfg := DefaultLibSQLConfig()
+	cfg.URL = "file:" + filepath.Join(t.TempDir(), "test.db")
+	cfg.EnableMetrics = false
+	cfg.MigrationPath = ""
+	return cfg
+}
+
+// openTestDB opens cfg and closes the database when the test ends
+func openTestDB(t *testing.T, cfg LibSQLConfig) *LibSQLDatabase {
+	t.Helper()
+	db, err := NewLibSQLDatabase(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("NewLibSQLDatabase: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestCloseStopsGoroutines(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.HealthCheckInterval = time.Hour
+	cfg.CheckpointInterval = time.Hour
+	cfg.OptimizeInterval = time.Hour
+	cfg.IdleCheckpointDelay = time.Hour
+	cfg.AutoTunePool = true
+	cfg.OnDataChanged = func() {}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	dir := t.TempDir()
+
+	open := func(i int) {
+		cfg := cfg
+		cfg.URL = fmt.Sprintf("file:%s", filepath.Join(dir, fmt.Sprintf("db%d.db", i)))
+		db, err := NewLibSQLDatabase(cfg, logger)
+		if err != nil {
+			t.Fatalf("NewLibSQLDatabase: %v", err)
+		}
+		if err := db.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	// The first open starts process-wide goroutines that are never meant to exit
+	open(0)
+	before := runtime.NumGoroutine()
+
+	for i := 1; i <= 20; i++ {
+		open(i)
+	}
+
+	// Goroutines that were told to stop may take a moment to be scheduled out
+	deadline := time.Now().Add(2 * time.Second)
+	after := runtime.NumGoroutine()
+	for after > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		after = runtime.NumGoroutine()
+	}
+	if after > before {
+		t.Errorf("goroutines grew from %d to %d after opening and closing 20 databases", before, after)
+	}
+}
+
+func TestMetricsWithTwoDatabases(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels [2]string
+	}{
+		{name: "same instance label", labels: [2]string{"", ""}},
+		{name: "distinct instance labels", labels: [2]string{"primary", "secondary"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reg := prometheus.NewRegistry()
+			for _, label := range tt.labels {
+				cfg := testConfig(t)
+				cfg.EnableMetrics = true
+				cfg.Registerer = reg
+				cfg.InstanceLabel = label
+				db := openTestDB(t, cfg)
+
+				if _, err := db.Exec(context.Background(), "create_table", "CREATE TABLE t (id INTEGER PRIMARY KEY)"); err != nil {
+					t.Fatalf("Exec: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestOpenCreatesDataDirectory(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "nested directory", path: filepath.Join("a", "b", "c", "test.db")},
+		{name: "with query parameters", path: filepath.Join("x", "y", "test.db") + "?cache=shared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig(t)
+			cfg.URL = "file:" + filepath.Join(t.TempDir(), tt.path)
+			db := openTestDB(t, cfg)
+
+			if _, err := db.Exec(context.Background(), "create_table", "CREATE TABLE t (id INTEGER PRIMARY KEY)"); err != nil {
+				t.Fatalf("Exec: %v", err)
+			}
+			if _, err := os.Stat(localPath(cfg.URL)); err != nil {
+				t.Errorf("database file wasn't created: %v", err)
+			}
+		})
+	}
+}
+
+func TestMemoryDatabase(t *testing.T) {
+	tests := []string{
+		":memory:",
+		"file::memory:",
+		"file::memory:?cache=shared",
+		"file:memtest?mode=memory",
+	}
+
+	for _, url := range tests {
+		t.Run(url, func(t *testing.T) {
+			ctx := context.Background()
+			cfg := testConfig(t)
+			cfg.URL = url
+			db := openTestDB(t, cfg)
+
+			if got := db.Stats().MaxOpenConnections; got != 1 {
+				t.Errorf("MaxOpenConnections = %d, want 1", got)
+			}
+
+			if _, err := db.Exec(ctx, "create_table", "CREATE TABLE kv (k TEXT PRIMARY KEY, v TEXT)"); err != nil {
+				t.Fatalf("create table: %v", err)
+			}
+			err := db.Transaction(ctx, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, "INSERT INTO kv (k, v) VALUES (?, ?)", "a", "1")
+				return err
+			})
+			if err != nil {
+				t.Fatalf("insert in transaction: %v", err)
+			}
+			if _, err := db.Exec(ctx, "insert", "INSERT INTO kv (k, v) VALUES (?, ?)", "b", "2"); err != nil {
+				t.Fatalf("insert: %v", err)
+			}
+
+			rows, err := db.Query(ctx, "select", "SELECT k, v FROM kv ORDER BY k")
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			defer rows.Close()
+
+			var got []string
+			for rows.Next() {
+				var k, v string
+				if err := rows.Scan(&k, &v); err != nil {
+					t.Fatalf("Scan: %v", err)
+				}
+				got = append(got, k+"="+v)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("rows: %v", err)
+			}
+			if want := []string{"a=1", "b=2"}; !slices.Equal(got, want) {
+				t.Errorf("read back %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestIsMemory(t *testing.T) {
+	tests := []struct {
+		url    string
+		memory bool
+	}{
+		{url: ":memory:", memory: true},
+		{url: "file::memory:", memory: true},
+		{url: "file::memory:?cache=shared", memory: true},
+		{url: "file:test?mode=memory&cache=shared", memory: true},
+		{url: "file:data/app.db", memory: false},
+		{url: "file:data/app.db?mode=ro", memory: false},
+		{url: "libsql://db.turso.io", memory: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			if got := isMemory(tt.url); got != tt.memory {
+				t.Errorf("isMemory(%q) = %v, want %v", tt.url, got, tt.memory)
+			}
+			if tt.memory && isLocalFile(tt.url) {
+				t.Errorf("isLocalFile(%q) = true for a memory database", tt.url)
+			}
+		})
+	}
+}
+
+func TestCancellationReason(t *testing.T) {
+	live := context.Background()
+	cancelled, cancel := context.WithCancel(live)
+	cancel()
+	expired, cancelExpired := context.WithDeadline(live, time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	interrupted := errors.New("interrupted")
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want string
+	}{
+		{name: "success", ctx: live, err: nil, want: ""},
+		{name: "success after cancel", ctx: cancelled, err: nil, want: ""},
+		{name: "query error", ctx: live, err: interrupted, want: ""},
+		{name: "cancelled context", ctx: cancelled, err: interrupted, want: "canceled"},
+		{name: "wrapped Canceled", ctx: live, err: fmt.Errorf("query: %w", context.Canceled), want: "canceled"},
+		{name: "expired context", ctx: expired, err: interrupted, want: "deadline_exceeded"},
+		{name: "wrapped DeadlineExceeded", ctx: live, err: fmt.Errorf("query: %w", context.DeadlineExceeded), want: "deadline_exceeded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cancellationReason(tt.ctx, tt.err); got != tt.want {
+				t.Errorf("cancellationReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCancelledQueryMetrics(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.EnableMetrics = true
+	cfg.Registerer = prometheus.NewRegistry()
+	db := openTestDB(t, cfg)
+	db.RegisterQueryType("cancel_test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if rows, err := db.Query(ctx, "cancel_test", "SELECT 1"); err == nil {
+		rows.Close()
+		t.Fatal("Query with a cancelled context succeeded")
+	}
+	if _, err := db.Exec(ctx, "cancel_test", "CREATE TABLE t (id INTEGER)"); err == nil {
+		t.Fatal("Exec with a cancelled context succeeded")
+	}
+
+	if got := testutil.ToFloat64(db.metrics.queryCancellations.WithLabelValues("cancel_test", "canceled")); got != 2 {
+		t.Errorf("cancellations = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(db.metrics.queryErrors.WithLabelValues("cancel_test")); got != 0 {
+		t.Errorf("query errors = %v, want 0", got)
+	}
+}
@@ -0,0 +1,142 @@
+// Package dbtest provides helpers for testing code that uses the database package
+package dbtest
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	database "discord.awfixer.ai/api/v2/pkg/cmd"
+)
+
+// counter keeps database names unique across tests in the same process
+var counter atomic.Uint64
+
+// NewTestDatabase opens an isolated in-memory database for t, applies the migrations in
+// migrations (skipped when nil), and closes it via t.Cleanup. Metrics are not registered
+// and logs go to t.Log, so parallel tests don't interfere with each other.
+func NewTestDatabase(t testing.TB, migrations fs.FS) *database.LibSQLDatabase {
+	t.Helper()
+
+	// A named shared-cache memory database is visible to every connection in this pool
+	// but to nothing else, unlike the anonymous :memory: which is per connection
+	name := fmt.Sprintf("dbtest_%d_%s", counter.Add(1), sanitize(t.Name()))
+	cfg := database.LibSQLConfig{
+		URL:          "file:" + name + "?mode=memory&cache=shared",
+		MaxOpenConns: 1,
+		MaxIdleConns: 1,
+		MigrationFS:  migrations,
+	}
+
+	db, err := database.NewLibSQLDatabase(cfg, tbLogger{t})
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Errorf("failed to close test database: %v", err)
+		}
+	})
+
+	if migrations != nil {
+		if err := db.Migrate(context.Background()); err != nil {
+			t.Fatalf("failed to migrate test database: %v", err)
+		}
+	}
+
+	return db
+}
+
+// AssertUsesIndex fails t if query's plan scans any table in full instead of using an
+// index. args are bound as a normal query would bind them.
+func AssertUsesIndex(t testing.TB, db *database.LibSQLDatabase, query string, args ...any) {
+	t.Helper()
+	AssertUsesIndexMinRows(t, db, 0, query, args...)
+}
+
+// AssertUsesIndexMinRows is AssertUsesIndex that tolerates full scans of tables holding
+// fewer than minRows rows, such as small lookup tables where a scan is cheap
+func AssertUsesIndexMinRows(t testing.TB, db *database.LibSQLDatabase, minRows int64, query string, args ...any) {
+	t.Helper()
+
+	ctx := context.Background()
+	steps, err := db.ExplainQueryPlan(ctx, query, args...)
+	if err != nil {
+		t.Fatalf("failed to explain query: %v", err)
+	}
+
+	for _, step := range steps {
+		table, ok := step.FullScan()
+		if !ok {
+			continue
+		}
+
+		if minRows > 0 {
+			rows, err := db.RefreshRowCount(ctx, table)
+			if err != nil {
+				t.Fatalf("failed to count rows: %v", err)
+			}
+			if rows < minRows {
+				continue
+			}
+		}
+
+		t.Errorf("query scans table %s without an index (%s)\nquery: %s\nplan:\n%s",
+			table, step.Detail, query, formatPlan(steps))
+	}
+}
+
+// formatPlan renders plan steps indented under their parents, like the sqlite3 shell
+func formatPlan(steps []database.PlanStep) string {
+	depth := make(map[int]int, len(steps))
+	var sb strings.Builder
+	for _, step := range steps {
+		depth[step.ID] = depth[step.Parent] + 1
+		sb.WriteString(strings.Repeat("  ", depth[step.ID]))
+		sb.WriteString(step.Detail)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// sanitize makes a test name safe to use in a database URL
+func sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// tbLogger adapts testing.TB to database.Logger
+type tbLogger struct {
+	t testing.TB
+}
+
+// Debug writes to the test log
+func (l tbLogger) Debug(msg string, args ...any) { l.log("DEBUG", msg, args) }
+
+// Info writes to the test log
+func (l tbLogger) Info(msg string, args ...any) { l.log("INFO", msg, args) }
+
+// Warn writes to the test log
+func (l tbLogger) Warn(msg string, args ...any) { l.log("WARN", msg, args) }
+
+// Error writes to the test log
+func (l tbLogger) Error(msg string, args ...any) { l.log("ERROR", msg, args) }
+
+// log writes a leveled message with key=value pairs to the test log
+func (l tbLogger) log(level, msg string, args []any) {
+	var sb strings.Builder
+	sb.WriteString(level)
+	sb.WriteString(" ")
+	sb.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&sb, " %v=%v", args[i], args[i+1])
+	}
+	l.t.Log(sb.String())
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// buildConnStr builds the driver DSN from cfg, adding the auth token for remote URLs and
+// read-only mode for local files with proper escaping while preserving any query
+// parameters already in the URL. Embedded replicas pass the token and SyncURL to their
+// connector instead.
+func buildConnStr(cfg LibSQLConfig) (string, error) {
+	readOnly := cfg.ReadOnly && isLocalFile(cfg.URL)
+	withToken := cfg.AuthToken != "" && !isLocalFile(cfg.URL)
+	if !withToken && !readOnly {
+		return cfg.URL, nil
+	}
+
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid database URL %q: %w", redactDSN(cfg.URL), err)
+	}
+
+	query := u.Query()
+	if withToken {
+		query.Set("authToken", cfg.AuthToken)
+	}
+	if readOnly {
+		query.Set("mode", "ro")
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// redactDSN strips userinfo, query parameters and fragments from a connection string
+// so it can be logged without leaking credentials
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		// Unparseable: drop everything that could hold parameters
+		if i := strings.IndexAny(dsn, "?#"); i >= 0 {
+			dsn = dsn[:i]
+		}
+		if at := strings.LastIndexByte(dsn, '@'); at >= 0 {
+			if scheme := strings.Index(dsn, "://"); scheme >= 0 && scheme < at {
+				dsn = dsn[:scheme+3] + dsn[at+1:]
+			}
+		}
+		return dsn
+	}
+
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// redactedError replaces credentials in an error message while keeping the error chain
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError scrubs the connection string and auth token from err's message.
+// Drivers sometimes echo the full DSN in their errors.
+func redactError(err error, cfg LibSQLConfig, connStr string) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := err.Error()
+	if connStr != "" {
+		msg = strings.ReplaceAll(msg, connStr, redactDSN(connStr))
+	}
+	if cfg.AuthToken != "" {
+		msg = strings.ReplaceAll(msg, cfg.AuthToken, "REDACTED")
+		msg = strings.ReplaceAll(msg, url.QueryEscape(cfg.AuthToken), "REDACTED")
+	}
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{msg: msg, err: err}
+}
@@ -0,0 +1,159 @@
+package database
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testToken = "s3cr3t+tok/en=="
+
+func TestRedactDSN(t *testing.T) {
+	tests := []struct {
+		name string
+		dsn  string
+		want string
+	}{
+		{name: "token parameter", dsn: "libsql://db.turso.io?authToken=" + testToken, want: "libsql://db.turso.io"},
+		{name: "userinfo", dsn: "libsql://user:" + testToken + "@db.turso.io/path", want: "libsql://db.turso.io/path"},
+		{name: "fragment", dsn: "file:data/app.db#" + testToken, want: "file:data/app.db"},
+		{name: "no credentials", dsn: "file:data/app.db", want: "file:data/app.db"},
+		{name: "unparseable", dsn: "libsql://user:pw@db.turso.io:bad port?authToken=" + testToken, want: "libsql://db.turso.io:bad port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redactDSN(tt.dsn); got != tt.want {
+				t.Errorf("redactDSN(%q) = %q, want %q", tt.dsn, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactError(t *testing.T) {
+	cfg := LibSQLConfig{URL: "libsql://db.turso.io", AuthToken: testToken}
+	connStr, err := buildConnStr(cfg)
+	if err != nil {
+		t.Fatalf("buildConnStr: %v", err)
+	}
+
+	cause := errors.New("dial failed")
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "full DSN", err: errors.Join(errors.New("open "+connStr), cause)},
+		{name: "raw token", err: errors.Join(errors.New("bad token "+testToken), cause)},
+		{name: "escaped token", err: errors.Join(errors.New("authToken="+strings.TrimPrefix(connStr, "libsql://db.turso.io?authToken=")), cause)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted := redactError(tt.err, cfg, connStr)
+			if strings.Contains(redacted.Error(), testToken) || strings.Contains(redacted.Error(), "s3cr3t") {
+				t.Errorf("redacted error still contains the token: %q", redacted)
+			}
+			if !errors.Is(redacted, cause) {
+				t.Error("redacted error lost its chain")
+			}
+		})
+	}
+}
+
+func TestTokenNotLogged(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	cfg := testConfig(t)
+	cfg.URL = "file:" + filepath.Join(t.TempDir(), "test.db") + "?authToken=" + testToken
+	db, err := NewLibSQLDatabase(cfg, logger)
+	if err != nil {
+		t.Fatalf("NewLibSQLDatabase: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if logs.Len() == 0 {
+		t.Fatal("expected log output")
+	}
+	if strings.Contains(logs.String(), "s3cr3t") {
+		t.Errorf("log output contains the token:\n%s", logs.String())
+	}
+}
+
+func TestBuildConnStr(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  LibSQLConfig
+		want string
+	}{
+		{
+			name: "remote without token",
+			cfg:  LibSQLConfig{URL: "libsql://db.turso.io"},
+			want: "libsql://db.turso.io",
+		},
+		{
+			name: "remote with token",
+			cfg:  LibSQLConfig{URL: "libsql://db.turso.io", AuthToken: testToken},
+			want: "libsql://db.turso.io?authToken=s3cr3t%2Btok%2Fen%3D%3D",
+		},
+		{
+			name: "remote with existing parameters",
+			cfg:  LibSQLConfig{URL: "libsql://db.turso.io?foo=bar", AuthToken: testToken},
+			want: "libsql://db.turso.io?authToken=s3cr3t%2Btok%2Fen%3D%3D&foo=bar",
+		},
+		{
+			name: "remote token replaces one in the URL",
+			cfg:  LibSQLConfig{URL: "libsql://db.turso.io?authToken=old", AuthToken: testToken},
+			want: "libsql://db.turso.io?authToken=s3cr3t%2Btok%2Fen%3D%3D",
+		},
+		{
+			name: "local file ignores token",
+			cfg:  LibSQLConfig{URL: "file:data/app.db", AuthToken: testToken},
+			want: "file:data/app.db",
+		},
+		{
+			name: "local read-only",
+			cfg:  LibSQLConfig{URL: "file:data/app.db", ReadOnly: true},
+			want: "file:data/app.db?mode=ro",
+		},
+		{
+			name: "local read-only with existing parameters",
+			cfg:  LibSQLConfig{URL: "file:data/app.db?foo=bar", ReadOnly: true},
+			want: "file:data/app.db?foo=bar&mode=ro",
+		},
+		{
+			name: "remote read-only",
+			cfg:  LibSQLConfig{URL: "libsql://db.turso.io", ReadOnly: true},
+			want: "libsql://db.turso.io",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := buildConnStr(tt.cfg)
+			if err != nil {
+				t.Fatalf("buildConnStr: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("buildConnStr() = %q, want %q", got, tt.want)
+			}
+
+			if tt.cfg.AuthToken == "" || isLocalFile(tt.cfg.URL) {
+				return
+			}
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatalf("result doesn't parse: %v", err)
+			}
+			if token := u.Query().Get("authToken"); token != tt.cfg.AuthToken {
+				t.Errorf("authToken decodes as %q, want %q", token, tt.cfg.AuthToken)
+			}
+		})
+	}
+}
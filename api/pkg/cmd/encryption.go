@@ -0,0 +1,130 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// cipherProbes are pragmas that only return a row when the SQLite build can encrypt:
+// SQLCipher answers cipher_version and SQLite3 Multiple Ciphers answers cipher. Builds
+// without a cipher ignore unknown pragmas, PRAGMA key included, and return nothing.
+var cipherProbes = []string{"PRAGMA cipher_version", "PRAGMA cipher"}
+
+// applyKey sets the encryption key on a fresh connection and verifies it by reading the
+// schema. A wrong key only surfaces on the first read, as "file is not a database".
+func applyKey(ctx context.Context, conn driver.Conn, key string) error {
+	if err := checkCipherSupport(ctx, conn); err != nil {
+		return err
+	}
+	if err := execConn(ctx, conn, "PRAGMA key = "+quoteLiteral(key)); err != nil {
+		return fmt.Errorf("failed to set encryption key: %w", err)
+	}
+	if err := execConn(ctx, conn, "SELECT count(*) FROM sqlite_master"); err != nil {
+		return keyError(err)
+	}
+	return nil
+}
+
+// checkCipherSupport returns ErrEncryptionUnsupported unless the connection's SQLite
+// build can encrypt. Without this check PRAGMA key silently does nothing and the
+// "encrypted" database is written in plaintext.
+func checkCipherSupport(ctx context.Context, conn driver.Conn) error {
+	for _, probe := range cipherProbes {
+		ok, err := queryConnHasRow(ctx, conn, probe)
+		if err != nil {
+			return fmt.Errorf("failed to probe encryption support: %w", err)
+		}
+		if ok {
+			return nil
+		}
+	}
+	return ErrEncryptionUnsupported
+}
+
+// queryConnHasRow runs query directly on a driver connection and reports whether it
+// returned at least one row
+func queryConnHasRow(ctx context.Context, conn driver.Conn, query string) (bool, error) {
+	var rows driver.Rows
+	var err error
+	if queryer, ok := conn.(driver.QueryerContext); ok {
+		rows, err = queryer.QueryContext(ctx, query, nil)
+	}
+	if rows == nil && (err == nil || err == driver.ErrSkip) {
+		stmt, prepErr := conn.Prepare(query)
+		if prepErr != nil {
+			return false, prepErr
+		}
+		defer stmt.Close()
+		if sq, ok := stmt.(driver.StmtQueryContext); ok {
+			rows, err = sq.QueryContext(ctx, nil)
+		} else {
+			// Fallback for drivers without StmtQueryContext
+			rows, err = stmt.Query(nil)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	dest := make([]driver.Value, len(rows.Columns()))
+	switch err := rows.Next(dest); err {
+	case nil:
+		return true, nil
+	case io.EOF:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// Rekey re-encrypts the local database with newKey. Connections opened afterwards use
+// the new key and idle connections holding the old one are closed, but connections in
+// use at the time will fail, so call it while the database is quiet.
+func (d *LibSQLDatabase) Rekey(ctx context.Context, newKey string) error {
+	if !isLocalFile(d.config.URL) {
+		return fmt.Errorf("rekey is only supported for local file databases")
+	}
+	if d.connector.key.Load() == nil {
+		return fmt.Errorf("rekey requires the database to be opened with an EncryptionKey")
+	}
+	if newKey == "" {
+		return fmt.Errorf("rekey requires a non-empty key")
+	}
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for rekey: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA rekey = "+quoteLiteral(newKey)); err != nil {
+		return fmt.Errorf("failed to rekey database: %w", keyError(err))
+	}
+	d.connector.key.Store(&newKey)
+
+	// Cycle the idle pool so no connection keeps using the old key
+	stats := d.db.Stats()
+	d.db.SetMaxIdleConns(0)
+	d.db.SetMaxIdleConns(d.config.MaxIdleConns)
+
+	d.logger.Info("database re-encrypted", "closed_idle_conns", stats.Idle)
+	return nil
+}
+
+// keyError maps SQLite's "file is not a database" to ErrEncryptionKey
+func keyError(err error) error {
+	if errorContains(err, "file is not a database") {
+		return fmt.Errorf("%w: %w", ErrEncryptionKey, err)
+	}
+	return err
+}
+
+// quoteLiteral quotes s as a SQL string literal. PRAGMA values can't be bound
+// parameters, so keys have to be inlined.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
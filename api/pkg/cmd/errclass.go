@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrorClass is how retry and circuit breaker logic treat an error
+type ErrorClass int
+
+const (
+	ErrorClassFatal      ErrorClass = iota // Not worth retrying
+	ErrorClassRetryable                    // Transient: busy, locked or a dropped connection
+	ErrorClassConstraint                   // A constraint rejected the write; the database is fine
+	ErrorClassCancelled                    // The caller's context ended
+)
+
+// String returns the class name
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassFatal:
+		return "fatal"
+	case ErrorClassRetryable:
+		return "retryable"
+	case ErrorClassConstraint:
+		return "constraint"
+	case ErrorClassCancelled:
+		return "cancelled"
+	default:
+		return "unknown"
+	}
+}
+
+// DefaultErrorClassifier classifies common SQLite and libSQL errors. Custom classifiers
+// can wrap it to special-case their own errors and defer to it for the rest.
+func DefaultErrorClassifier(err error) ErrorClass {
+	switch {
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassCancelled
+	case IsConstraintViolation(err):
+		return ErrorClassConstraint
+	case isRetryable(err):
+		return ErrorClassRetryable
+	default:
+		return ErrorClassFatal
+	}
+}
+
+// classify runs the configured ErrorClassifier, or DefaultErrorClassifier
+func (d *LibSQLDatabase) classify(err error) ErrorClass {
+	if d.config.ErrorClassifier != nil {
+		return d.config.ErrorClassifier(err)
+	}
+	return DefaultErrorClassifier(err)
+}
+
+// retryable reports whether the classifier considers err transient
+func (d *LibSQLDatabase) retryable(err error) bool {
+	return err != nil && d.classify(err) == ErrorClassRetryable
+}
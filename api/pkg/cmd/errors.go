@@ -0,0 +1,110 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+)
+
+// Sentinel errors for common failure modes; test with errors.Is
+var (
+	ErrNoURL                 = errors.New("database URL is required")
+	ErrConnFailed            = errors.New("database connection failed")
+	ErrMigrationDirty        = errors.New("database migration state is dirty")
+	ErrMigrationLockLost     = errors.New("migration lock was lost to another instance")
+	ErrSyncNotEnabled        = errors.New("sync is not enabled: requires a file: URL and SyncURL")
+	ErrReadOnlyStorage       = errors.New("database storage is read-only")
+	ErrCircuitOpen           = errors.New("database circuit breaker is open")
+	ErrEncryptionKey         = errors.New("database encryption key is wrong or the file is not encrypted")
+	ErrEncryptionUnsupported = errors.New("database driver was built without encryption support: EncryptionKey can't be honored")
+	ErrStorageFull           = errors.New("database storage is full: writes are disabled")
+	ErrUnknownDatabase       = errors.New("database is not registered")
+	ErrConstraint            = errors.New("database constraint violated")
+
+	// ErrNoRows aliases sql.ErrNoRows so errors.Is matches either
+	ErrNoRows = sql.ErrNoRows
+)
+
+// SQLite primary result codes used for error classification
+const (
+	sqliteBusy       = 5
+	sqliteLocked     = 6
+	sqliteIOErr      = 10
+	sqliteFull       = 13
+	sqliteConstraint = 19
+
+	sqliteBusySnapshot = sqliteBusy | 2<<8 // Extended code for a stale WAL read snapshot
+)
+
+// sqliteCoder is implemented by driver errors that expose a SQLite result code
+type sqliteCoder interface {
+	Code() int
+}
+
+// sqliteCode extracts the primary SQLite result code from err, if the driver exposes one
+func sqliteCode(err error) (int, bool) {
+	var coder sqliteCoder
+	if !errors.As(err, &coder) {
+		return 0, false
+	}
+	// Extended result codes carry the primary code in the low byte
+	return coder.Code() & 0xff, true
+}
+
+// IsBusy reports whether err means the database was busy or locked
+func IsBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := sqliteCode(err); ok {
+		return code == sqliteBusy || code == sqliteLocked
+	}
+	return errorContains(err, "database is locked", "database table is locked", "sqlite_busy", "sqlite_locked")
+}
+
+// IsSnapshotConflict reports whether err is SQLITE_BUSY_SNAPSHOT: in WAL mode, a
+// transaction that read an older snapshot tried to write after another writer committed
+func IsSnapshotConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	var coder sqliteCoder
+	if errors.As(err, &coder) {
+		return coder.Code() == sqliteBusySnapshot
+	}
+	return errorContains(err, "sqlite_busy_snapshot", "busy_snapshot")
+}
+
+// IsConstraintViolation reports whether err is a UNIQUE, NOT NULL, CHECK or FOREIGN KEY violation
+func IsConstraintViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := sqliteCode(err); ok {
+		return code == sqliteConstraint
+	}
+	return errorContains(err, "constraint failed", "sqlite_constraint")
+}
+
+// isStorageError reports whether err means the disk is full or failing
+func isStorageError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if code, ok := sqliteCode(err); ok {
+		return code == sqliteFull || code == sqliteIOErr
+	}
+	return errorContains(err, "database or disk is full", "disk i/o error", "sqlite_full", "sqlite_ioerr")
+}
+
+// errorContains reports whether err's message contains any of markers, ignoring case.
+// Remote drivers often surface only a message, so classification falls back to it.
+func errorContains(err error, markers ...string) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range markers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// PlanStep is one row of EXPLAIN QUERY PLAN output
+type PlanStep struct {
+	ID     int    // Step id, referenced by children's Parent
+	Parent int    // Id of the enclosing step, or 0 at the top level
+	Detail string // SQLite's description, such as "SEARCH users USING INDEX idx_email (email=?)"
+}
+
+// ExplainQueryPlan runs EXPLAIN QUERY PLAN for query with args bound as a normal query
+// would bind them, and returns the plan's steps in output order
+func (d *LibSQLDatabase) ExplainQueryPlan(ctx context.Context, query string, args ...any) ([]PlanStep, error) {
+	rows, err := d.Query(ctx, "explain_query_plan", "EXPLAIN QUERY PLAN "+query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to explain query: %w", err)
+	}
+	defer rows.Close()
+
+	var steps []PlanStep
+	for rows.Next() {
+		var step PlanStep
+		var notUsed int
+		if err := rows.Scan(&step.ID, &step.Parent, &notUsed, &step.Detail); err != nil {
+			return nil, fmt.Errorf("failed to scan query plan step: %w", err)
+		}
+		steps = append(steps, step)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query plan: %w", err)
+	}
+
+	return steps, nil
+}
+
+// FullScan reports whether the step scans a whole table without an index and, if so,
+// which table. Newer SQLite writes "SCAN users", older versions "SCAN TABLE users";
+// scans driven by an index ("SCAN users USING INDEX ..."), virtual tables, subqueries
+// and constant rows don't count.
+func (s PlanStep) FullScan() (string, bool) {
+	rest, ok := strings.CutPrefix(s.Detail, "SCAN ")
+	if !ok || strings.Contains(rest, " USING ") || strings.Contains(rest, " VIRTUAL TABLE ") {
+		return "", false
+	}
+	rest = strings.TrimPrefix(rest, "TABLE ")
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || fields[0] == "SUBQUERY" || fields[0] == "CONSTANT" {
+		return "", false
+	}
+	return fields[0], true
+}
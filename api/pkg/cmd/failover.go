@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+)
+
+// failoverBackoff is how long a connection that hit a failover waits before database/sql
+// retries the statement on another one, giving the new primary a moment to come up
+const failoverBackoff = 250 * time.Millisecond
+
+// isFailover reports whether err means the remote primary went away before the
+// statement ran, as happens while Turso fails over. The signatures are:
+//
+//   - connection refused (ECONNREFUSED): nothing is listening yet
+//   - HTTP 502 Bad Gateway and 503 Service Unavailable from the edge proxy
+//   - Hrana STREAM_EXPIRED / "stream not found": the server lost the connection's session
+//
+// Resets, broken pipes and timeouts are deliberately excluded: the statement may
+// already have run, so replaying it could apply a write twice.
+func isFailover(err error) bool {
+	if err == nil || errors.Is(err, driver.ErrBadConn) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	return errorContains(err,
+		"502 bad gateway", "status 502", "status code 502",
+		"503 service unavailable", "status 503", "status code 503",
+		"stream_expired", "stream expired", "stream not found",
+	)
+}
+
+// failover evicts the connection when err is a failover error. It marks the connection
+// broken, notifies the connector, waits failoverBackoff and returns err wrapped in
+// driver.ErrBadConn, which makes database/sql retry on a fresh connection. Statements in
+// a transaction aren't retried, since the transaction died with the connection.
+func (c *pooledConn) failover(ctx context.Context, err error) error {
+	if !isFailover(err) {
+		return err
+	}
+
+	c.broken = true
+	if notify := c.connector.onFailover.Load(); notify != nil {
+		(*notify)(err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(failoverBackoff):
+	}
+	return fmt.Errorf("%w: %w", driver.ErrBadConn, err)
+}
+
+// handleFailover logs a failover and re-pings the pool in the background so a fresh
+// connection to the new primary is ready for the retry. Concurrent failovers share one
+// ping, and none starts once Close has begun.
+func (d *LibSQLDatabase) handleFailover(err error) {
+	d.logger.Warn("database failover detected, evicting connection", "error", err)
+
+	d.bgMu.Lock()
+	defer d.bgMu.Unlock()
+	if d.bgCtx == nil || d.bgCtx.Err() != nil || !d.repinging.CompareAndSwap(false, true) {
+		return
+	}
+	d.goBackground(d.bgCtx, d.repingAfterFailover)
+}
+
+// repingAfterFailover pings the pool until it reconnects, ConnectTimeout passes or
+// ctx ends
+func (d *LibSQLDatabase) repingAfterFailover(ctx context.Context) {
+	defer d.repinging.Store(false)
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.ConnectTimeout)
+	defer cancel()
+	if err := d.db.PingContext(ctx); err != nil {
+		d.logger.Warn("re-ping after failover failed", "error", err)
+		return
+	}
+	d.logger.Info("reconnected after failover")
+}
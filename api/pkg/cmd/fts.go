@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// SearchResult is a row matched by Search, ranked by bm25 (lower is more relevant)
+type SearchResult struct {
+	RowID int64
+	Rank  float64
+}
+
+// FTSTableName returns the name of the FTS5 table SetupFTS creates for sourceTable
+func FTSTableName(sourceTable string) string {
+	return sourceTable + "_fts"
+}
+
+// SetupFTS creates an external-content FTS5 table indexing columns of sourceTable,
+// adds triggers that keep it in sync with inserts, updates and deletes, and rebuilds
+// the index from existing rows. It is safe to call on every startup.
+func (d *LibSQLDatabase) SetupFTS(ctx context.Context, sourceTable string, columns []string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("full-text search on %s requires at least one column", sourceTable)
+	}
+	for _, name := range append([]string{sourceTable}, columns...) {
+		if !identifierPattern.MatchString(name) {
+			return fmt.Errorf("invalid identifier %q", name)
+		}
+	}
+
+	fts := FTSTableName(sourceTable)
+	cols := strings.Join(columns, ", ")
+	newCols := "new." + strings.Join(columns, ", new.")
+	oldCols := "old." + strings.Join(columns, ", old.")
+
+	// External-content tables are updated with the special 'delete' command
+	stmts := []string{
+		fmt.Sprintf("CREATE VIRTUAL TABLE IF NOT EXISTS %s USING fts5(%s, content=%s, content_rowid=rowid)",
+			fts, cols, quoteIdent(sourceTable)),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_ai AFTER INSERT ON %[2]s BEGIN
+	INSERT INTO %[1]s(rowid, %[3]s) VALUES (new.rowid, %[4]s);
+END`, fts, sourceTable, cols, newCols),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_ad AFTER DELETE ON %[2]s BEGIN
+	INSERT INTO %[1]s(%[1]s, rowid, %[3]s) VALUES ('delete', old.rowid, %[4]s);
+END`, fts, sourceTable, cols, oldCols),
+		fmt.Sprintf(`CREATE TRIGGER IF NOT EXISTS %[1]s_au AFTER UPDATE ON %[2]s BEGIN
+	INSERT INTO %[1]s(%[1]s, rowid, %[3]s) VALUES ('delete', old.rowid, %[4]s);
+	INSERT INTO %[1]s(rowid, %[3]s) VALUES (new.rowid, %[5]s);
+END`, fts, sourceTable, cols, oldCols, newCols),
+		fmt.Sprintf("INSERT INTO %[1]s(%[1]s) VALUES ('rebuild')", fts),
+	}
+
+	err := d.Transaction(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set up full-text search on %s: %w", sourceTable, err)
+	}
+
+	d.logger.Info("full-text search ready", "table", sourceTable, "fts_table", fts, "columns", columns)
+	return nil
+}
+
+// Search runs a full-text query against ftsTable and returns up to limit matches,
+// best first. The query is treated as plain terms: FTS5 operators and special
+// characters in user input are escaped rather than interpreted.
+func (d *LibSQLDatabase) Search(ctx context.Context, ftsTable, query string, limit int) ([]SearchResult, error) {
+	if !identifierPattern.MatchString(ftsTable) {
+		return nil, fmt.Errorf("invalid identifier %q", ftsTable)
+	}
+
+	match := escapeFTSQuery(query)
+	if match == "" {
+		return []SearchResult{}, nil
+	}
+
+	stmt := fmt.Sprintf("SELECT rowid, bm25(%[1]s) AS rank FROM %[1]s WHERE %[1]s MATCH ? ORDER BY rank LIMIT ?", ftsTable)
+	rows, err := d.Query(ctx, "fts_search", stmt, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", ftsTable, err)
+	}
+	defer rows.Close()
+
+	results := []SearchResult{}
+	for rows.Next() {
+		var r SearchResult
+		if err := rows.Scan(&r.RowID, &r.Rank); err != nil {
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search results: %w", err)
+	}
+
+	return results, nil
+}
+
+// escapeFTSQuery quotes each whitespace-separated term as an FTS5 string so that
+// operators (AND, NEAR, *, ^, :) match literally; terms are implicitly ANDed
+func escapeFTSQuery(query string) string {
+	terms := strings.Fields(query)
+	for i, term := range terms {
+		terms[i] = `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,235 @@
+package database
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"modernc.org/sqlite"
+)
+
+var (
+	errorType = reflect.TypeFor[error]()
+	anyType   = reflect.TypeFor[any]()
+)
+
+// registeredFuncs tracks what has been handed to the driver's process-wide function
+// table, which rejects a name registered twice
+var registeredFuncs = struct {
+	sync.Mutex
+	byName map[string]registeredFunc
+}{byName: make(map[string]registeredFunc)}
+
+// registeredFunc identifies a registration well enough to tell a repeat from a clash
+type registeredFunc struct {
+	code  uintptr // Entry point of fn; closures from the same literal share it
+	nArgs int32
+	pure  bool
+}
+
+// RegisterFunc makes fn callable from SQL as name. fn must be a func whose parameters
+// and first result are int64, int, float64, string, []byte, bool or any, optionally
+// returning an error second; a variadic fn accepts any number of arguments. SQL NULL
+// arrives as the zero value (nil for any). Mark pure functions, whose result depends
+// only on their arguments, so SQLite can use them in indexes and constant-fold them.
+//
+// The function is registered with modernc.org/sqlite, which adds it to every connection
+// opened from then on, process-wide. Existing connections are retired so the whole pool
+// picks it up: idle ones are closed now and in-use ones once they are returned. Only
+// local file databases are supported: remote servers can't run Go code, and a memory
+// database's single connection can't be replaced without losing its data. Registering
+// the same function again, from this or another database, succeeds; a different
+// function under a name already in use is an error.
+func (d *LibSQLDatabase) RegisterFunc(name string, fn any, pure bool) error {
+	if !isLocalFile(d.config.URL) {
+		return fmt.Errorf("failed to register function %s: only local file databases support custom functions", name)
+	}
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("failed to register function %q: invalid name", name)
+	}
+
+	nArgs, scalar, err := scalarFunc(fn)
+	if err != nil {
+		return fmt.Errorf("failed to register function %s: %w", name, err)
+	}
+
+	if err := registerDriverFunc(name, fn, nArgs, pure, scalar); err != nil {
+		return fmt.Errorf("failed to register function %s: %w", name, err)
+	}
+
+	// Retire every connection opened before the function existed
+	d.connector.generation.Add(1)
+	stats := d.db.Stats()
+	d.db.SetMaxIdleConns(0)
+	d.db.SetMaxIdleConns(d.config.MaxIdleConns)
+
+	d.logger.Info("registered SQL function",
+		"name", name,
+		"args", nArgs,
+		"pure", pure,
+		"closed_idle_conns", stats.Idle,
+	)
+	return nil
+}
+
+// registerDriverFunc registers fn with the driver once per process
+func registerDriverFunc(name string, fn any, nArgs int32, pure bool, scalar func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error)) error {
+	want := registeredFunc{code: reflect.ValueOf(fn).Pointer(), nArgs: nArgs, pure: pure}
+
+	registeredFuncs.Lock()
+	defer registeredFuncs.Unlock()
+
+	if have, ok := registeredFuncs.byName[name]; ok {
+		if have != want {
+			return fmt.Errorf("a different function is already registered under this name")
+		}
+		return nil
+	}
+
+	err := sqlite.RegisterFunction(name, &sqlite.FunctionImpl{
+		NArgs:         nArgs,
+		Deterministic: pure,
+		Scalar:        scalar,
+	})
+	if err != nil {
+		return err
+	}
+	registeredFuncs.byName[name] = want
+	return nil
+}
+
+// scalarFunc validates fn's signature and adapts it to the driver's calling convention.
+// nArgs is -1 for variadic functions.
+func scalarFunc(fn any) (int32, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error), error) {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return 0, nil, fmt.Errorf("expected a func, got %T", fn)
+	}
+	t := v.Type()
+
+	params := make([]reflect.Type, t.NumIn())
+	for i := range params {
+		params[i] = t.In(i)
+		if t.IsVariadic() && i == len(params)-1 {
+			params[i] = params[i].Elem()
+		}
+		if !isFuncValueType(params[i]) {
+			return 0, nil, fmt.Errorf("parameter %d has unsupported type %s", i+1, params[i])
+		}
+	}
+
+	switch {
+	case t.NumOut() == 1 && isFuncValueType(t.Out(0)):
+	case t.NumOut() == 2 && isFuncValueType(t.Out(0)) && t.Out(1) == errorType:
+	default:
+		return 0, nil, fmt.Errorf("must return one supported value, optionally followed by an error")
+	}
+
+	nArgs := int32(len(params))
+	if t.IsVariadic() {
+		nArgs = -1
+	}
+
+	scalar := func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		in := make([]reflect.Value, len(args))
+		for i, arg := range args {
+			param := params[min(i, len(params)-1)]
+			converted, err := convertFuncArg(arg, param)
+			if err != nil {
+				return nil, fmt.Errorf("argument %d: %w", i+1, err)
+			}
+			in[i] = converted
+		}
+
+		out := v.Call(in)
+		if len(out) == 2 && !out[1].IsNil() {
+			return nil, out[1].Interface().(error)
+		}
+		return funcResult(out[0]), nil
+	}
+
+	return nArgs, scalar, nil
+}
+
+// isFuncValueType reports whether t can cross the Go/SQL boundary in a custom function
+func isFuncValueType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int64, reflect.Int, reflect.Float64, reflect.String, reflect.Bool:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8
+	case reflect.Interface:
+		return t == anyType
+	}
+	return false
+}
+
+// convertFuncArg converts a SQLite value to the parameter type t
+func convertFuncArg(arg driver.Value, t reflect.Type) (reflect.Value, error) {
+	if arg == nil {
+		return reflect.Zero(t), nil
+	}
+	if t == anyType {
+		v := reflect.New(t).Elem()
+		v.Set(reflect.ValueOf(arg))
+		return v, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Int64, reflect.Int:
+		if n, ok := arg.(int64); ok {
+			return reflect.ValueOf(n).Convert(t), nil
+		}
+	case reflect.Float64:
+		switch n := arg.(type) {
+		case float64:
+			return reflect.ValueOf(n).Convert(t), nil
+		case int64:
+			return reflect.ValueOf(float64(n)).Convert(t), nil
+		}
+	case reflect.Bool:
+		if n, ok := arg.(int64); ok {
+			return reflect.ValueOf(n != 0).Convert(t), nil
+		}
+	case reflect.String:
+		switch s := arg.(type) {
+		case string:
+			return reflect.ValueOf(s).Convert(t), nil
+		case []byte:
+			return reflect.ValueOf(string(s)).Convert(t), nil
+		}
+	case reflect.Slice:
+		switch b := arg.(type) {
+		case []byte:
+			return reflect.ValueOf(b).Convert(t), nil
+		case string:
+			return reflect.ValueOf([]byte(b)).Convert(t), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("cannot use %T as %s", arg, t)
+}
+
+// funcResult converts a function's result to a value SQLite can store
+func funcResult(v reflect.Value) driver.Value {
+	switch v.Kind() {
+	case reflect.Int64, reflect.Int:
+		return v.Int()
+	case reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		if v.Bool() {
+			return int64(1)
+		}
+		return int64(0)
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		return v.Bytes()
+	}
+	// any: pass through whatever the function returned
+	return v.Interface()
+}
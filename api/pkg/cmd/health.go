@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Liveness is a cheap check that the database answers a ping
+func (d *LibSQLDatabase) Liveness(ctx context.Context) (err error) {
+	ctx, span := d.startSpan(ctx, "db.liveness", "")
+	defer func() { endSpan(span, err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, d.config.HealthTimeout)
+	defer cancel()
+
+	if err := d.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+
+	return nil
+}
+
+// Readiness verifies the database can serve traffic: it pings, runs a query,
+// probes a write against a temp table, and checks read replicas
+func (d *LibSQLDatabase) Readiness(ctx context.Context) (err error) {
+	ctx, span := d.startSpan(ctx, "db.readiness", "SELECT 1")
+	defer func() { endSpan(span, err) }()
+
+	// Set a timeout for health check
+	ctx, cancel := context.WithTimeout(ctx, d.config.HealthTimeout)
+	defer cancel()
+
+	// Ping the database
+	if err := d.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database ping failed: %w", err)
+	}
+
+	// Run a simple query to verify functionality
+	var result int
+	err = d.db.QueryRowContext(ctx, "SELECT 1").Scan(&result)
+	if err != nil {
+		return fmt.Errorf("health query failed: %w", err)
+	}
+
+	if result != 1 {
+		return fmt.Errorf("unexpected health check result: %d", result)
+	}
+
+	if !d.config.ReadOnly {
+		if err := d.writeProbe(ctx); err != nil {
+			return err
+		}
+	}
+
+	// At least one read replica must be reachable when replicas are configured
+	if err := d.replicaHealth(ctx); err != nil {
+		return err
+	}
+
+	// Stop routing traffic to an embedded replica that has fallen too far behind
+	if d.config.MaxReplicationLag > 0 {
+		if lag, err := d.ReplicationLag(ctx); err == nil && lag > d.config.MaxReplicationLag {
+			return fmt.Errorf("replica is stale: last synced %s ago (max %s)", lag.Round(time.Second), d.config.MaxReplicationLag)
+		}
+	}
+
+	return nil
+}
+
+// writeProbe verifies the database accepts writes using a connection-local temp table
+func (d *LibSQLDatabase) writeProbe(ctx context.Context) error {
+	// Temp tables are per connection, so pin one for the whole probe
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("write probe failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	probes := []string{
+		"CREATE TEMP TABLE IF NOT EXISTS _readiness_probe (checked_at INTEGER NOT NULL)",
+		"INSERT INTO _readiness_probe (checked_at) VALUES (unixepoch())",
+		"DELETE FROM _readiness_probe",
+	}
+	for _, probe := range probes {
+		if _, err := conn.ExecContext(ctx, probe); err != nil {
+			return fmt.Errorf("write probe failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// TestConnection checks that cfg can reach the database: it opens a single connection,
+// pings, runs SELECT 1 and closes it, without registering metrics or starting background
+// work. It is meant for deploy-time probes. The whole check is bounded by
+// ConnectTimeout (default 5s), a missing local database file is reported rather than
+// created, and credentials are redacted from returned errors.
+func TestConnection(ctx context.Context, cfg LibSQLConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid database configuration: %w", err)
+	}
+	if isLocalFile(cfg.URL) {
+		if _, err := os.Stat(localPath(cfg.URL)); err != nil {
+			return fmt.Errorf("%w: %w", ErrConnFailed, err)
+		}
+	}
+
+	timeout := cfg.ConnectTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	connStr, err := buildConnStr(cfg)
+	if err != nil {
+		return err
+	}
+	connector, err := newConnector(cfg, connStr)
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", redactError(err, cfg, connStr))
+	}
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		return fmt.Errorf("%w: failed to ping database: %w", ErrConnFailed, redactError(err, cfg, connStr))
+	}
+
+	var one int
+	if err := db.QueryRowContext(ctx, "SELECT 1").Scan(&one); err != nil {
+		return fmt.Errorf("%w: test query failed: %w", ErrConnFailed, redactError(err, cfg, connStr))
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// IndexSpec describes an index for EnsureIndex
+type IndexSpec struct {
+	Name        string   // Index name
+	Table       string   // Indexed table
+	Columns     []string // Plain columns, indexed first and in order
+	Expressions []string // SQL expressions such as "lower(email)", indexed after Columns
+	Unique      bool     // Create a UNIQUE index
+	Where       string   // Optional condition making this a partial index, without the WHERE keyword
+}
+
+// EnsureIndex creates the index described by spec unless one with that name already
+// exists. An existing index is left as is even if its definition differs. Names must be
+// plain identifiers. Expressions and Where are SQL and must come from code, not user
+// input: they are checked to be a single balanced expression without statement
+// separators or comments, which rules out injecting another statement but not
+// arbitrary expressions.
+func (d *LibSQLDatabase) EnsureIndex(ctx context.Context, spec IndexSpec) error {
+	stmt, err := spec.createSQL()
+	if err != nil {
+		return fmt.Errorf("invalid index %q: %w", spec.Name, err)
+	}
+
+	if _, err := d.ExecContext(ctx, "ensure_index", stmt); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", spec.Name, err)
+	}
+	return nil
+}
+
+// createSQL validates spec and builds its CREATE INDEX IF NOT EXISTS statement
+func (s IndexSpec) createSQL() (string, error) {
+	for _, ident := range append([]string{s.Name, s.Table}, s.Columns...) {
+		if !identifierPattern.MatchString(ident) {
+			return "", fmt.Errorf("invalid identifier %q", ident)
+		}
+	}
+	if len(s.Columns)+len(s.Expressions) == 0 {
+		return "", errors.New("at least one column or expression is required")
+	}
+
+	keys := make([]string, 0, len(s.Columns)+len(s.Expressions))
+	for _, column := range s.Columns {
+		keys = append(keys, quoteIdent(column))
+	}
+	for _, expr := range s.Expressions {
+		if err := checkExpression(expr); err != nil {
+			return "", fmt.Errorf("invalid expression %q: %w", expr, err)
+		}
+		keys = append(keys, "("+expr+")")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("CREATE ")
+	if s.Unique {
+		sb.WriteString("UNIQUE ")
+	}
+	fmt.Fprintf(&sb, "INDEX IF NOT EXISTS %s ON %s (%s)", quoteIdent(s.Name), quoteIdent(s.Table), strings.Join(keys, ", "))
+
+	if s.Where != "" {
+		if err := checkExpression(s.Where); err != nil {
+			return "", fmt.Errorf("invalid WHERE clause %q: %w", s.Where, err)
+		}
+		sb.WriteString(" WHERE ")
+		sb.WriteString(s.Where)
+	}
+
+	return sb.String(), nil
+}
+
+// checkExpression rejects SQL fragments that could end the statement or hide text:
+// semicolons and comments outside quotes, unbalanced parentheses and unterminated quotes
+func checkExpression(expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		return errors.New("empty expression")
+	}
+
+	depth := 0
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '\'', '"', '`', '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			end := strings.IndexByte(expr[i+1:], closing)
+			if end < 0 {
+				return errors.New("unterminated quote")
+			}
+			// A doubled quote is an escaped quote and simply starts the next quoted run
+			i += end + 1
+		case ';':
+			return errors.New("statement separators are not allowed")
+		case '-', '/':
+			if i+1 < len(expr) && (c == '-' && expr[i+1] == '-' || c == '/' && expr[i+1] == '*') {
+				return errors.New("comments are not allowed")
+			}
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return errors.New("unbalanced parentheses")
+			}
+		}
+	}
+	if depth != 0 {
+		return errors.New("unbalanced parentheses")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// jsonPathPattern matches SQLite JSON paths: $ followed by .key, ."quoted key",
+// [N], [#] or [#-N] segments
+var jsonPathPattern = regexp.MustCompile(`^\$(\.[A-Za-z_][A-Za-z0-9_]*|\."[^"]*"|\[(\d+|#(-\d+)?)\])*$`)
+
+// JSONExtract reads path from the JSON column of the row where idCol = id and unmarshals
+// it into dest. A missing path or JSON null leaves dest untouched and returns nil;
+// ErrNoRows is returned when no row matches.
+func (d *LibSQLDatabase) JSONExtract(ctx context.Context, table, idCol string, id any, jsonCol, path string, dest any) error {
+	if err := validateJSONPath(path); err != nil {
+		return err
+	}
+
+	// json_quote turns scalar results back into JSON text so everything unmarshals uniformly
+	query := fmt.Sprintf("SELECT json_quote(json_extract(%s, ?)) FROM %s WHERE %s = ?",
+		quoteIdent(jsonCol), quoteIdent(table), quoteIdent(idCol))
+
+	var raw sql.NullString
+	if err := d.QueryRow(ctx, "json_extract", query, path, id).Scan(&raw); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoRows
+		}
+		return fmt.Errorf("failed to extract %s from %s.%s: %w", path, table, jsonCol, err)
+	}
+	if !raw.Valid || raw.String == "null" {
+		return nil
+	}
+
+	if err := json.Unmarshal([]byte(raw.String), dest); err != nil {
+		return fmt.Errorf("failed to decode %s from %s.%s: %w", path, table, jsonCol, err)
+	}
+	return nil
+}
+
+// JSONSet writes value, marshalled as JSON, at path in the JSON column of the row where
+// idCol = id. Returns ErrNoRows when no row matches.
+func (d *LibSQLDatabase) JSONSet(ctx context.Context, table, idCol string, id any, jsonCol, path string, value any) error {
+	if err := validateJSONPath(path); err != nil {
+		return err
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode value for %s: %w", path, err)
+	}
+
+	// json() marks the argument as JSON so objects aren't stored as quoted strings
+	query := fmt.Sprintf("UPDATE %s SET %s = json_set(COALESCE(%s, '{}'), ?, json(?)) WHERE %s = ?",
+		quoteIdent(table), quoteIdent(jsonCol), quoteIdent(jsonCol), quoteIdent(idCol))
+
+	res, err := d.ExecContext(ctx, "json_set", query, path, string(encoded), id)
+	if err != nil {
+		return fmt.Errorf("failed to set %s on %s.%s: %w", path, table, jsonCol, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read json_set result: %w", err)
+	}
+	if n == 0 {
+		return ErrNoRows
+	}
+	return nil
+}
+
+// validateJSONPath rejects paths SQLite's json1 functions would not accept
+func validateJSONPath(path string) error {
+	if !jsonPathPattern.MatchString(path) {
+		return fmt.Errorf("invalid JSON path %q", path)
+	}
+	return nil
+}
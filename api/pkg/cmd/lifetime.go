@@ -0,0 +1,79 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+)
+
+// sweepAgedConns periodically cycles idle connections so expired ones are closed
+// even when the pool is quiet and nothing would otherwise check them
+func (d *LibSQLDatabase) sweepAgedConns(ctx context.Context) {
+	ticker := time.NewTicker(max(d.config.ConnMaxLifetimeJitter/2, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.sweepIdle(ctx)
+		}
+	}
+}
+
+// sweepAcquireTimeout bounds each checkout in sweepIdle, which only wants connections
+// that are already idle and must never queue behind real callers
+const sweepAcquireTimeout = 100 * time.Millisecond
+
+// sweepIdle checks out the currently idle connections and closes the expired ones.
+// database/sql hands out the most recently returned idle connection, so live ones are
+// held until the end to make each checkout reach a different one; expired ones are
+// closed as they are found. The sweep stops early once the pool has nothing idle left
+// or another caller starts waiting for a connection.
+func (d *LibSQLDatabase) sweepIdle(ctx context.Context) {
+	stats := d.db.Stats()
+	waits := stats.WaitCount
+
+	var held []*sql.Conn
+	defer func() {
+		for _, conn := range held {
+			conn.Close()
+		}
+	}()
+
+	closed := 0
+	for range stats.Idle {
+		if stats = d.db.Stats(); stats.Idle == 0 || stats.WaitCount > waits {
+			break
+		}
+
+		acquireCtx, cancel := context.WithTimeout(ctx, sweepAcquireTimeout)
+		conn, err := d.db.Conn(acquireCtx)
+		cancel()
+		if err != nil {
+			break
+		}
+
+		expired := false
+		conn.Raw(func(dc any) error {
+			if aged, ok := dc.(*pooledConn); ok && aged.expired() {
+				// Returning ErrBadConn makes database/sql close the connection
+				expired = true
+				return driver.ErrBadConn
+			}
+			return nil
+		})
+		if expired {
+			closed++
+			conn.Close()
+			continue
+		}
+		held = append(held, conn)
+	}
+
+	if closed > 0 {
+		d.logger.Debug("closed aged connections", "count", closed)
+	}
+}
@@ -0,0 +1,101 @@
+package database
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// writeTxTracker remembers which write transactions are open in this process so lock
+// contention can name the likely holder. SQLite defers taking the write lock until a
+// transaction first writes, so an open write transaction is a suspect, not proof.
+type writeTxTracker struct {
+	mu   sync.Mutex
+	next uint64
+	open map[uint64]writeTx
+}
+
+// writeTx is an open write transaction
+type writeTx struct {
+	caller  string // First caller outside this package, as "func file:line"
+	started time.Time
+}
+
+// newWriteTxTracker creates an empty tracker
+func newWriteTxTracker() *writeTxTracker {
+	return &writeTxTracker{open: make(map[uint64]writeTx)}
+}
+
+// begin records a write transaction started by caller and returns its id
+func (t *writeTxTracker) begin(caller string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next++
+	t.open[t.next] = writeTx{caller: caller, started: time.Now()}
+	return t.next
+}
+
+// end forgets the transaction with id
+func (t *writeTxTracker) end(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.open, id)
+}
+
+// oldest returns the longest-running write transaction other than exclude
+func (t *writeTxTracker) oldest(exclude uint64) (writeTx, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var found writeTx
+	var ok bool
+	for id, tx := range t.open {
+		if id != exclude && (!ok || tx.started.Before(found.started)) {
+			found, ok = tx, true
+		}
+	}
+	return found, ok
+}
+
+// txCaller returns the first caller outside this package as "func file:line". It is
+// cheaper than txOrigin's full stack, so it is captured for every write transaction.
+func txCaller() string {
+	pcs := make([]uintptr, maxTxOriginFrames)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			return frame.Function + " " + frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// observeLockContention logs a statement that gave up waiting for a lock, naming the
+// oldest other write transaction open in this process as the likely holder. Holders
+// in other processes can't be seen. self is the failing transaction's own id, or 0.
+func (d *LibSQLDatabase) observeLockContention(ctx context.Context, queryType, query string, self uint64, err error) {
+	if d.metrics != nil {
+		d.metrics.lockContention.WithLabelValues(d.queryLabel(queryType)).Inc()
+	}
+
+	fields := []any{
+		"query_type", queryType,
+		"statement", truncateStatement(query),
+		"busy_timeout", d.config.BusyTimeout,
+		"error", err,
+	}
+	if holder, ok := d.writeTxs.oldest(self); ok {
+		fields = append(fields,
+			"lock_holder", holder.caller,
+			"lock_held_for", time.Since(holder.started).Round(time.Millisecond),
+		)
+	}
+	d.logger.Warn("lock contention: statement gave up waiting for a database lock", append(fields, logTags(ctx)...)...)
+}
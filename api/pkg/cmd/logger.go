@@ -0,0 +1,32 @@
+package database
+
+import "log/slog"
+
+// Logger is the logging interface used by the database. *slog.Logger satisfies it
+// directly; other libraries (zap's SugaredLogger, zerolog wrappers) only need these
+// four methods taking a message and alternating key-value pairs.
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// SlogLogger adapts a *slog.Logger to Logger, falling back to slog.Default when nil
+func SlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		return slog.Default()
+	}
+	return l
+}
+
+// resolveLogger replaces a missing logger, including a typed nil *slog.Logger, with slog.Default
+func resolveLogger(l Logger) Logger {
+	if sl, ok := l.(*slog.Logger); ok {
+		return SlogLogger(sl)
+	}
+	if l == nil {
+		return slog.Default()
+	}
+	return l
+}
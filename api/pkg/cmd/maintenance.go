@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// maintenanceQueryType labels maintenance statements in query metrics
+const maintenanceQueryType = "maintenance"
+
+// optimizeTimeout bounds PRAGMA optimize, which normally finishes in milliseconds
+const optimizeTimeout = 30 * time.Second
+
+// integrityCheckTimeout bounds integrity checks, which scan the whole database
+const integrityCheckTimeout = 10 * time.Minute
+
+// IntegrityCheck runs PRAGMA integrity_check and returns any problems found.
+// An empty slice means the database is intact.
+func (d *LibSQLDatabase) IntegrityCheck(ctx context.Context) ([]string, error) {
+	return d.runIntegrityCheck(ctx, "integrity_check")
+}
+
+// QuickCheck runs the faster PRAGMA quick_check, which skips index consistency checks
+func (d *LibSQLDatabase) QuickCheck(ctx context.Context) ([]string, error) {
+	return d.runIntegrityCheck(ctx, "quick_check")
+}
+
+// runIntegrityCheck runs the given check pragma and collects reported problems
+func (d *LibSQLDatabase) runIntegrityCheck(ctx context.Context, pragma string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, integrityCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	rows, err := d.db.QueryContext(ctx, "PRAGMA "+pragma)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run %s: %w", pragma, err)
+	}
+	defer rows.Close()
+
+	problems := []string{}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, fmt.Errorf("failed to scan %s result: %w", pragma, err)
+		}
+		// A single "ok" row means no problems were found
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s results: %w", pragma, err)
+	}
+
+	if len(problems) > 0 {
+		d.logger.Error("database integrity problems found",
+			"check", pragma,
+			"problems", len(problems),
+			"first", problems[0],
+		)
+		if d.metrics != nil {
+			d.metrics.integrityProblems.WithLabelValues(pragma).Add(float64(len(problems)))
+		}
+	} else {
+		d.logger.Info("database integrity check passed", "check", pragma, "duration", time.Since(start))
+	}
+
+	return problems, nil
+}
+
+// Vacuum rebuilds the database file to reclaim free pages. It must run outside a
+// transaction, so it goes straight to the pool in autocommit mode.
+func (d *LibSQLDatabase) Vacuum(ctx context.Context) error {
+	if !isLocalFile(d.config.URL) {
+		d.logger.Warn("vacuum requested against a remote database", "url", redactDSN(d.config.URL))
+		return fmt.Errorf("vacuum is only supported for local file databases")
+	}
+	if d.config.ReadOnly {
+		return fmt.Errorf("failed to vacuum: %w", ErrReadOnlyStorage)
+	}
+
+	if err := d.runMaintenance(ctx, "VACUUM"); err != nil {
+		return fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return nil
+}
+
+// Analyze refreshes query planner statistics with ANALYZE followed by PRAGMA optimize
+func (d *LibSQLDatabase) Analyze(ctx context.Context) error {
+	if err := d.runMaintenance(ctx, "ANALYZE"); err != nil {
+		return fmt.Errorf("failed to analyze database: %w", err)
+	}
+	if err := d.runMaintenance(ctx, "PRAGMA optimize"); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
+	}
+	return nil
+}
+
+// canOptimize reports whether PRAGMA optimize applies; remote servers manage their
+// own statistics and read-only databases can't store new ones
+func (d *LibSQLDatabase) canOptimize() bool {
+	return isLocalFile(d.config.URL) && !d.config.ReadOnly
+}
+
+// optimizeLoop runs PRAGMA optimize on OptimizeInterval
+func (d *LibSQLDatabase) optimizeLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.config.OptimizeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			optCtx, cancel := context.WithTimeout(ctx, optimizeTimeout)
+			if err := d.runMaintenance(optCtx, "PRAGMA optimize"); err != nil {
+				d.logger.Warn("scheduled optimize failed", "error", err)
+			}
+			cancel()
+		}
+	}
+}
+
+// runMaintenance executes a maintenance statement and records it under the maintenance label
+func (d *LibSQLDatabase) runMaintenance(ctx context.Context, stmt string) error {
+	start := time.Now()
+	_, err := d.db.ExecContext(ctx, stmt)
+	duration := time.Since(start)
+	d.observeQuery(ctx, maintenanceQueryType, stmt, duration, err)
+	if err != nil {
+		return err
+	}
+
+	d.logger.Info("database maintenance complete", "statement", stmt, "duration", duration)
+	return nil
+}
@@ -0,0 +1,201 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// migrationLockTTL is how long a lock may be held before another instance may take
+	// it over, so a crashed instance can't block migrations forever
+	migrationLockTTL = 10 * time.Minute
+
+	// migrationLockPoll is how often waiting instances check whether the lock was released
+	migrationLockPoll = 500 * time.Millisecond
+
+	// migrationLockHeartbeat is how often the holder refreshes the lock so a migration
+	// running longer than migrationLockTTL keeps it
+	migrationLockHeartbeat = time.Minute
+)
+
+// ensureMigrationLockTable creates the single-row table used as the migration lock
+func (d *LibSQLDatabase) ensureMigrationLockTable(ctx context.Context) error {
+	_, err := d.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS migration_lock (
+		id          INTEGER PRIMARY KEY CHECK (id = 1),
+		owner       TEXT NOT NULL,
+		acquired_at INTEGER NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migration_lock table: %w", err)
+	}
+	return nil
+}
+
+// acquireMigrationLock tries once to take the migration lock for owner. The claim is a
+// single compare-and-set statement: local files serialize it under SQLite's exclusive
+// write lock, and remote libSQL runs it atomically on the server, so exactly one
+// instance wins. A lock older than migrationLockTTL is considered abandoned.
+//
+// Local files use this row too rather than holding BEGIN EXCLUSIVE for the whole run:
+// migrations execute on the pool's other connections, which an exclusive transaction
+// on the locking connection would block, and the row works the same for every process
+// sharing the file.
+func (d *LibSQLDatabase) acquireMigrationLock(ctx context.Context, owner string) (bool, error) {
+	res, err := d.db.ExecContext(ctx, `INSERT INTO migration_lock (id, owner, acquired_at)
+		VALUES (1, ?, unixepoch())
+		ON CONFLICT (id) DO UPDATE SET owner = excluded.owner, acquired_at = excluded.acquired_at
+		WHERE migration_lock.acquired_at < unixepoch() - ?`,
+		owner, int64(migrationLockTTL.Seconds()),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read migration lock result: %w", err)
+	}
+	return n == 1, nil
+}
+
+// refreshMigrationLock renews owner's claim on the lock, reporting false if another
+// instance took it over
+func (d *LibSQLDatabase) refreshMigrationLock(ctx context.Context, owner string) (bool, error) {
+	res, err := d.db.ExecContext(ctx,
+		"UPDATE migration_lock SET acquired_at = unixepoch() WHERE id = 1 AND owner = ?", owner)
+	if err != nil {
+		return false, fmt.Errorf("failed to refresh migration lock: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to read migration lock result: %w", err)
+	}
+	return n == 1, nil
+}
+
+// heartbeatMigrationLock refreshes owner's lock every migrationLockHeartbeat until ctx
+// ends. Once the lock is taken over, or can't be refreshed for longer than
+// migrationLockTTL so another instance may take it, it calls lost.
+func (d *LibSQLDatabase) heartbeatMigrationLock(ctx context.Context, owner string, lost context.CancelCauseFunc) {
+	ticker := time.NewTicker(migrationLockHeartbeat)
+	defer ticker.Stop()
+
+	refreshed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		held, err := d.refreshMigrationLock(ctx, owner)
+		switch {
+		case err != nil && time.Since(refreshed) < migrationLockTTL:
+			d.logger.Warn("failed to refresh migration lock, retrying", "error", err)
+			continue
+		case err != nil:
+			d.logger.Error("migration lock expired while migrating", "error", err)
+			lost(fmt.Errorf("%w: %w", ErrMigrationLockLost, err))
+			return
+		case !held:
+			d.logger.Error("migration lock was taken over by another instance while migrating")
+			lost(ErrMigrationLockLost)
+			return
+		}
+		refreshed = time.Now()
+	}
+}
+
+// releaseMigrationLock drops the lock if owner still holds it
+func (d *LibSQLDatabase) releaseMigrationLock(ctx context.Context, owner string) {
+	// Release even if the migration's context was cancelled
+	ctx = context.WithoutCancel(ctx)
+	if _, err := d.db.ExecContext(ctx, "DELETE FROM migration_lock WHERE id = 1 AND owner = ?", owner); err != nil {
+		d.logger.Error("failed to release migration lock", "error", err)
+	}
+}
+
+// waitMigrationLock blocks until no instance holds the migration lock
+func (d *LibSQLDatabase) waitMigrationLock(ctx context.Context) error {
+	ticker := time.NewTicker(migrationLockPoll)
+	defer ticker.Stop()
+
+	for {
+		var held int
+		err := d.db.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM migration_lock WHERE acquired_at >= unixepoch() - ?",
+			int64(migrationLockTTL.Seconds()),
+		).Scan(&held)
+		if err != nil {
+			return fmt.Errorf("failed to check migration lock: %w", err)
+		}
+		if held == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("gave up waiting for migration lock: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// lockMigrations takes the migration lock and keeps it refreshed until released. It
+// returns a context for the migration that is cancelled if the lock is lost and a
+// release func that takes the migration's error and adds ErrMigrationLockLost to it
+// when losing the lock is what stopped the migration.
+//
+// When another instance holds the lock, lockMigrations waits for it to finish and
+// reports waited without taking the lock: that instance did the work, so the caller
+// should only check the result. release is then nil.
+func (d *LibSQLDatabase) lockMigrations(ctx context.Context) (lockCtx context.Context, release func(error) error, waited bool, err error) {
+	if err := d.ensureMigrationLockTable(ctx); err != nil {
+		return nil, nil, false, err
+	}
+
+	owner := migrationLockOwner()
+	acquired, err := d.acquireMigrationLock(ctx, owner)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if !acquired {
+		d.logger.Info("another instance is migrating, waiting for it to finish")
+		if err := d.waitMigrationLock(ctx); err != nil {
+			return nil, nil, true, err
+		}
+		return ctx, nil, true, nil
+	}
+
+	lockCtx, lost := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d.heartbeatMigrationLock(lockCtx, owner, lost)
+	}()
+
+	release = func(err error) error {
+		lost(nil)
+		<-done
+		d.releaseMigrationLock(ctx, owner)
+		if cause := context.Cause(lockCtx); err != nil && errors.Is(cause, ErrMigrationLockLost) {
+			return fmt.Errorf("%w (%w)", err, cause)
+		}
+		return err
+	}
+	return lockCtx, release, false, nil
+}
+
+// migrationLockOwner identifies this process in the lock row
+func migrationLockOwner() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
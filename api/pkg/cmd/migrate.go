@@ -0,0 +1,481 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationFilePattern matches files like 0001_init.up.sql / 0001_init.down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// migration describes a single versioned migration on disk
+type migration struct {
+	version  int
+	name     string
+	upFile   string
+	downFile string
+}
+
+// Migrate applies all pending migrations from MigrationFS or MigrationPath in version order
+func (d *LibSQLDatabase) Migrate(ctx context.Context) (err error) {
+	migrations, source, err := d.loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	// Serialize with other instances; a waiter only confirms the winner's work
+	ctx, release, waited, err := d.lockMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	if waited {
+		return d.checkMigrated(ctx, migrations)
+	}
+	defer func() { err = release(err) }()
+
+	if err := d.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	if err := d.checkDirty(ctx); err != nil {
+		return err
+	}
+
+	applied, err := d.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		if m.upFile == "" {
+			return fmt.Errorf("migration %d (%s) has no up file", m.version, m.name)
+		}
+
+		script, err := fs.ReadFile(source, m.upFile)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %d: %w", m.version, err)
+		}
+
+		// Record the version as dirty first so an interrupted apply is detected on the next run
+		start := time.Now()
+		_, err = d.db.ExecContext(ctx,
+			"INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, 1)",
+			m.version, m.name,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record migration %d: %w", m.version, err)
+		}
+
+		err = d.Transaction(ctx, func(tx *sql.Tx) error {
+			if err := execScript(ctx, tx, string(script)); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "UPDATE schema_migrations SET dirty = 0 WHERE version = ?", m.version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %d (%s): %w", m.version, m.name, err)
+		}
+
+		d.logger.Info("applied migration", "version", m.version, "name", m.name)
+		d.notifyMigration(m, "up", time.Since(start))
+	}
+
+	return nil
+}
+
+// checkMigrated verifies, without writing, that another instance left every migration
+// applied and none dirty
+func (d *LibSQLDatabase) checkMigrated(ctx context.Context, migrations []migration) error {
+	if err := d.checkDirty(ctx); err != nil {
+		return err
+	}
+
+	applied, err := d.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for _, m := range migrations {
+		if !applied[m.version] {
+			pending++
+		}
+	}
+	if pending > 0 {
+		return fmt.Errorf("another instance finished migrating but %d migrations are still pending", pending)
+	}
+
+	d.logger.Info("migrations were applied by another instance")
+	return nil
+}
+
+// PlannedMigration is a pending migration reported by MigratePlan
+type PlannedMigration struct {
+	Version  int
+	Name     string
+	Filename string
+	SQL      string
+}
+
+// MigratePlan returns the pending migrations Migrate would apply, in order,
+// without writing anything to the database
+func (d *LibSQLDatabase) MigratePlan(ctx context.Context) ([]PlannedMigration, error) {
+	migrations, source, err := d.loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	// Avoid ensureMigrationsTable here: plan mode must not create the table
+	var tableExists int
+	err = d.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+	).Scan(&tableExists)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for schema_migrations table: %w", err)
+	}
+
+	applied := map[int]bool{}
+	if tableExists > 0 {
+		if err := d.checkDirty(ctx); err != nil {
+			return nil, err
+		}
+		if applied, err = d.appliedVersions(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var plan []PlannedMigration
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		if m.upFile == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up file", m.version, m.name)
+		}
+
+		script, err := fs.ReadFile(source, m.upFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %d: %w", m.version, err)
+		}
+
+		plan = append(plan, PlannedMigration{
+			Version:  m.version,
+			Name:     m.name,
+			Filename: m.upFile,
+			SQL:      string(script),
+		})
+	}
+
+	return plan, nil
+}
+
+// Rollback reverts the last steps applied migrations using their down files
+func (d *LibSQLDatabase) Rollback(ctx context.Context, steps int) (err error) {
+	if steps <= 0 {
+		return fmt.Errorf("rollback steps must be positive, got %d", steps)
+	}
+
+	migrations, source, err := d.loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	// Serialize with migrations running in other instances; the schema they leave
+	// behind may not be the one the caller meant to roll back
+	ctx, release, waited, err := d.lockMigrations(ctx)
+	if err != nil {
+		return err
+	}
+	if waited {
+		return errors.New("cannot roll back: another instance migrated while this one waited, check the schema and retry")
+	}
+	defer func() { err = release(err) }()
+
+	if err := d.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	if err := d.checkDirty(ctx); err != nil {
+		return err
+	}
+
+	applied, err := d.appliedVersions(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Walk applied migrations from newest to oldest
+	var targets []migration
+	for i := len(migrations) - 1; i >= 0 && len(targets) < steps; i-- {
+		if applied[migrations[i].version] {
+			targets = append(targets, migrations[i])
+		}
+	}
+
+	if len(targets) < steps {
+		return fmt.Errorf("cannot roll back %d migrations: only %d applied", steps, len(targets))
+	}
+
+	// Check every down file up front so we never stop halfway through
+	for _, m := range targets {
+		if m.downFile == "" {
+			return fmt.Errorf("migration %d (%s) has no down file", m.version, m.name)
+		}
+	}
+
+	for _, m := range targets {
+		script, err := fs.ReadFile(source, m.downFile)
+		if err != nil {
+			return fmt.Errorf("failed to read down migration %d: %w", m.version, err)
+		}
+
+		start := time.Now()
+		if _, err := d.db.ExecContext(ctx, "UPDATE schema_migrations SET dirty = 1 WHERE version = ?", m.version); err != nil {
+			return fmt.Errorf("failed to mark migration %d dirty: %w", m.version, err)
+		}
+
+		err = d.Transaction(ctx, func(tx *sql.Tx) error {
+			if err := execScript(ctx, tx, string(script)); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = ?", m.version)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to roll back migration %d (%s): %w", m.version, m.name, err)
+		}
+
+		d.logger.Info("rolled back migration", "version", m.version, "name", m.name)
+		d.notifyMigration(m, "down", time.Since(start))
+	}
+
+	return nil
+}
+
+// SchemaVersion returns the highest applied migration version, or 0 if none
+func (d *LibSQLDatabase) SchemaVersion(ctx context.Context) (int, error) {
+	if err := d.ensureMigrationsTable(ctx); err != nil {
+		return 0, err
+	}
+
+	var version int
+	err := d.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	return version, nil
+}
+
+// ForceVersion marks migrations up to version as cleanly applied and forgets any later ones,
+// without running SQL. It is the escape hatch for clearing a dirty state after an operator
+// has repaired the schema by hand.
+func (d *LibSQLDatabase) ForceVersion(ctx context.Context, version int) error {
+	if version < 0 {
+		return fmt.Errorf("invalid migration version %d", version)
+	}
+
+	migrations, _, err := d.loadMigrations()
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("cannot force version %d: only %d migrations exist", version, len(migrations))
+	}
+
+	if err := d.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	err = d.Transaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version > ?", version); err != nil {
+			return err
+		}
+		for _, m := range migrations[:version] {
+			_, err := tx.ExecContext(ctx,
+				`INSERT INTO schema_migrations (version, name, dirty) VALUES (?, ?, 0)
+				ON CONFLICT (version) DO UPDATE SET dirty = 0`,
+				m.version, m.name,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
+	}
+
+	d.logger.Warn("forced migration version", "version", version)
+	return nil
+}
+
+// notifyMigration reports a completed migration to OnMigration. It runs after the
+// migration's transaction has committed so a slow hook can't hold it open.
+func (d *LibSQLDatabase) notifyMigration(m migration, direction string, duration time.Duration) {
+	if d.config.OnMigration != nil {
+		d.config.OnMigration(m.version, m.name, direction, duration)
+	}
+}
+
+// checkDirty returns ErrMigrationDirty if any migration was left partially applied
+func (d *LibSQLDatabase) checkDirty(ctx context.Context) error {
+	var version int
+	err := d.db.QueryRowContext(ctx,
+		"SELECT version FROM schema_migrations WHERE dirty = 1 ORDER BY version LIMIT 1",
+	).Scan(&version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check migration state: %w", err)
+	}
+
+	return fmt.Errorf("%w: version %d (inspect the schema, then use ForceVersion)", ErrMigrationDirty, version)
+}
+
+// ensureMigrationsTable creates the schema_migrations tracking table if needed
+func (d *LibSQLDatabase) ensureMigrationsTable(ctx context.Context) error {
+	_, err := d.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		name       TEXT NOT NULL,
+		dirty      INTEGER NOT NULL DEFAULT 0,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	// Tables created before dirty tracking existed lack the column
+	var hasDirty int
+	err = d.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM pragma_table_info('schema_migrations') WHERE name = 'dirty'",
+	).Scan(&hasDirty)
+	if err != nil {
+		return fmt.Errorf("failed to inspect schema_migrations table: %w", err)
+	}
+	if hasDirty == 0 {
+		if _, err := d.db.ExecContext(ctx, "ALTER TABLE schema_migrations ADD COLUMN dirty INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return fmt.Errorf("failed to add dirty column to schema_migrations: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// appliedVersions returns the set of migration versions already recorded
+func (d *LibSQLDatabase) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	rows, err := d.db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int]bool)
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// migrationSource returns MigrationFS if set, otherwise the MigrationPath directory
+func (d *LibSQLDatabase) migrationSource() (fs.FS, error) {
+	if d.config.MigrationFS != nil {
+		return d.config.MigrationFS, nil
+	}
+	if d.config.MigrationPath == "" {
+		return nil, fmt.Errorf("migration source is not configured: set MigrationFS or MigrationPath")
+	}
+	return os.DirFS(d.config.MigrationPath), nil
+}
+
+// loadMigrations reads the migration source and returns migrations sorted by version
+func (d *LibSQLDatabase) loadMigrations() ([]migration, fs.FS, error) {
+	source, err := d.migrationSource()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read migration directory: %w", err)
+	}
+
+	// ReadDir returns entries sorted by filename, so files are visited in lexical order
+	byVersion := make(map[int]*migration)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, nil, fmt.Errorf("invalid migration filename %q: expected NNNN_name.up.sql or NNNN_name.down.sql", entry.Name())
+		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: match[2]}
+			byVersion[version] = m
+		} else if m.name != match[2] {
+			return nil, nil, fmt.Errorf("conflicting names for migration %d: %q and %q", version, m.name, match[2])
+		}
+
+		if match[3] == "up" {
+			m.upFile = entry.Name()
+		} else {
+			m.downFile = entry.Name()
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	// Versions must be contiguous starting from 1
+	for i, m := range migrations {
+		if m.version != i+1 {
+			return nil, nil, fmt.Errorf("gap in migration versions: expected %d, found %d (%s)", i+1, m.version, m.name)
+		}
+	}
+
+	return migrations, source, nil
+}
+
+// execScript runs each statement of a multi-statement script in order on tx
+func execScript(ctx context.Context, tx *sql.Tx, script string) error {
+	for i, stmt := range splitStatements(script) {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// stateChangeThreshold is how many consecutive probes must disagree with the current
+// state before the monitor reports a transition, so a single blip doesn't flap
+const stateChangeThreshold = 2
+
+// healthMonitor probes liveness on HealthCheckInterval and reports debounced
+// healthy/unhealthy transitions to OnStateChange
+func (d *LibSQLDatabase) healthMonitor(ctx context.Context) {
+	ticker := time.NewTicker(d.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	healthy := true // The pool was reachable when the database was opened
+	streak := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := d.Liveness(ctx)
+			if ctx.Err() != nil {
+				return
+			}
+			d.recordBreakerProbe(ctx, err)
+			if err == nil && d.storage.degraded.Load() {
+				d.probeStorage(ctx)
+			}
+
+			if (err == nil) == healthy {
+				streak = 0
+				continue
+			}
+
+			streak++
+			if streak < stateChangeThreshold {
+				continue
+			}
+
+			healthy = err == nil
+			streak = 0
+			if healthy {
+				d.logger.Info("database became healthy")
+			} else {
+				d.logger.Error("database became unhealthy", "error", err)
+			}
+
+			if d.config.OnStateChange != nil {
+				d.config.OnStateChange(healthy)
+			}
+		}
+	}
+}
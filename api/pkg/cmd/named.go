@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// NamedQuery runs a query using :name placeholders bound from args
+func (d *LibSQLDatabase) NamedQuery(ctx context.Context, query string, args map[string]any) (*sql.Rows, error) {
+	bound, positional, err := bindNamed(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return d.Query(ctx, "named_query", bound, positional...)
+}
+
+// NamedExec runs a statement using :name placeholders bound from args
+func (d *LibSQLDatabase) NamedExec(ctx context.Context, query string, args map[string]any) (sql.Result, error) {
+	bound, positional, err := bindNamed(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return d.ExecContext(ctx, "named_exec", bound, positional...)
+}
+
+// bindNamed rewrites :name placeholders to positional ? and orders args to match.
+// Placeholders inside string literals, quoted identifiers and comments are left untouched.
+func bindNamed(query string, args map[string]any) (string, []any, error) {
+	var (
+		out        strings.Builder
+		positional []any
+	)
+	out.Grow(len(query))
+
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Quoted literal or identifier; a doubled quote is an escaped quote
+			j := i + 1
+			for j < len(query) {
+				if query[j] == c {
+					if j+1 < len(query) && query[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			end := min(j+1, len(query))
+			out.WriteString(query[i:end])
+			i = end
+
+		case c == '[':
+			end := strings.IndexByte(query[i:], ']')
+			if end < 0 {
+				end = len(query) - i - 1
+			}
+			out.WriteString(query[i : i+end+1])
+			i += end + 1
+
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			out.WriteString(query[i : i+end])
+			i += end
+
+		case c == '/' && i+1 < len(query) && query[i+1] == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query) - i
+			} else {
+				end += 4
+			}
+			out.WriteString(query[i : i+end])
+			i += end
+
+		case c == ':' && i+1 < len(query) && isIdentStart(query[i+1]):
+			j := i + 2
+			for j < len(query) && isIdentPart(query[j]) {
+				j++
+			}
+			name := query[i+1 : j]
+			value, ok := args[name]
+			if !ok {
+				return "", nil, fmt.Errorf("missing value for named parameter :%s", name)
+			}
+			out.WriteByte('?')
+			positional = append(positional, value)
+			i = j
+
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+
+	return out.String(), positional, nil
+}
+
+// isIdentStart reports whether c can begin a parameter name
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// isIdentPart reports whether c can continue a parameter name
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
@@ -0,0 +1,133 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"sync/atomic"
+)
+
+// openResources counts result sets and prepared statements the driver has handed out
+// but that haven't been closed yet. A steadily climbing row count means a caller is
+// forgetting rows.Close() and pinning a connection with it.
+type openResources struct {
+	rows  atomic.Int64
+	stmts atomic.Int64
+}
+
+// trackedStmt counts a prepared statement until it is closed and tracks the rows its
+// queries return
+type trackedStmt struct {
+	driver.Stmt
+	conn   *pooledConn
+	closed bool
+}
+
+// Close releases the statement's count and closes it
+func (s *trackedStmt) Close() error {
+	if !s.closed {
+		s.closed = true
+		s.conn.open.stmts.Add(-1)
+	}
+	return s.Stmt.Close()
+}
+
+// ExecContext forwards to the driver under the statement timeout
+func (s *trackedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, cancel := s.conn.statementContext(ctx)
+	defer cancel()
+
+	var result driver.Result
+	var err error
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = e.ExecContext(ctx, args)
+	} else {
+		// Fallback for drivers without StmtExecContext
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	if err != nil {
+		return nil, s.conn.failover(ctx, err)
+	}
+	return result, nil
+}
+
+// QueryContext forwards to the driver under the statement timeout, tracking the rows
+func (s *trackedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, cancel := s.conn.statementContext(ctx)
+
+	var rows driver.Rows
+	var err error
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		// Fallback for drivers without StmtQueryContext
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	if err != nil {
+		cancel()
+		return nil, s.conn.failover(ctx, err)
+	}
+
+	return s.conn.trackRows(ctx, rows, cancel), nil
+}
+
+// CheckNamedValue forwards to the driver, or defers to the default conversion
+func (s *trackedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// ColumnConverter forwards to the driver so its argument conversion still applies
+func (s *trackedStmt) ColumnConverter(idx int) driver.ValueConverter {
+	if c, ok := s.Stmt.(driver.ColumnConverter); ok {
+		return c.ColumnConverter(idx)
+	}
+	return driver.DefaultParameterConverter
+}
+
+// namedValues converts args for the legacy Stmt.Exec and Stmt.Query, which take no names
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, driver.ErrSkip
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// OpenRows returns the number of result sets currently open on the primary. Each one
+// holds a connection, so a count that stays above MaxOpenConns points to a missing
+// rows.Close().
+func (d *LibSQLDatabase) OpenRows() int64 {
+	return d.connector.open.rows.Load()
+}
+
+// OpenStatements returns the number of prepared statements currently open on the
+// primary's connections, including those held by the statement cache
+func (d *LibSQLDatabase) OpenStatements() int64 {
+	return d.connector.open.stmts.Load()
+}
+
+// checkOpenRows warns once each time open rows climb past MaxOpenConns, a sign that
+// rows are being leaked. leaking carries the previous state between calls.
+func (d *LibSQLDatabase) checkOpenRows(leaking bool) bool {
+	rows := d.OpenRows()
+	limit := int64(d.db.Stats().MaxOpenConnections)
+	over := limit > 0 && rows > limit
+	if over && !leaking {
+		d.logger.Warn("open result sets exceed MaxOpenConns; rows are probably not being closed",
+			"open_rows", rows,
+			"max_open_conns", limit,
+		)
+	}
+	return over
+}
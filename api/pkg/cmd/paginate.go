@@ -0,0 +1,195 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// PageRequest describes one page of a keyset-paginated query
+type PageRequest struct {
+	Query        string // Base SELECT; it is wrapped as a subquery, so it may have its own WHERE
+	Args         []any  // Arguments for Query
+	CursorColumn string // Unique, ordered column the pages are keyed on
+	Cursor       string // NextCursor from the previous page, empty for the first page
+	PageSize     int    // Maximum rows per page
+	Descending   bool   // Walk the cursor column from high to low
+}
+
+// Page is a single page of results
+type Page[T any] struct {
+	Items      []T
+	NextCursor string // Opaque token for the following page, empty on the last page
+}
+
+// Paginate runs req.Query with keyset pagination on req.CursorColumn and scans rows into
+// T like QueryRowStruct. T must have a field mapped to the cursor column. Unlike OFFSET,
+// the cost of a page doesn't grow with how far into the result set it is.
+func Paginate[T any](ctx context.Context, d *LibSQLDatabase, req PageRequest) (Page[T], error) {
+	var page Page[T]
+
+	elemType := reflect.TypeOf((*T)(nil)).Elem()
+	if elemType.Kind() != reflect.Struct {
+		return page, fmt.Errorf("Paginate requires a struct type, got %s", elemType)
+	}
+	if req.PageSize <= 0 {
+		return page, fmt.Errorf("page size must be positive, got %d", req.PageSize)
+	}
+
+	fields := structFields(elemType)
+	cursorIndex, ok := fields[req.CursorColumn]
+	if !ok {
+		return page, fmt.Errorf("cursor column %q has no matching field in %s", req.CursorColumn, elemType)
+	}
+
+	op, order := ">", "ASC"
+	if req.Descending {
+		op, order = "<", "DESC"
+	}
+
+	column := quoteIdent(req.CursorColumn)
+	query := "SELECT * FROM (" + req.Query + ")"
+	args := append([]any(nil), req.Args...)
+	if req.Cursor != "" {
+		value, err := decodeCursor(req.Cursor)
+		if err != nil {
+			return page, err
+		}
+		query += fmt.Sprintf(" WHERE %s %s ?", column, op)
+		args = append(args, value)
+	}
+	// Fetch one extra row to learn whether another page follows
+	query += fmt.Sprintf(" ORDER BY %s %s LIMIT ?", column, order)
+	args = append(args, req.PageSize+1)
+
+	rows, err := d.Query(ctx, "paginate", query, args...)
+	if err != nil {
+		return page, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return page, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	more := false
+	for rows.Next() {
+		if len(page.Items) == req.PageSize {
+			more = true
+			break
+		}
+
+		var item T
+		if err := scanStruct(rows, columns, fields, reflect.ValueOf(&item).Elem()); err != nil {
+			return page, err
+		}
+		page.Items = append(page.Items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return page, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	if more {
+		last := reflect.ValueOf(&page.Items[len(page.Items)-1]).Elem()
+		page.NextCursor, err = encodeCursor(last.FieldByIndex(cursorIndex).Interface())
+		if err != nil {
+			return page, err
+		}
+	}
+
+	return page, rows.Close()
+}
+
+// cursorToken is the JSON inside a cursor. Kind tags values whose JSON form would
+// otherwise come back as a different type.
+type cursorToken struct {
+	Kind  string `json:"k,omitempty"` // "time", "blob" or empty for plain JSON values
+	Value any    `json:"v"`
+}
+
+// encodeCursor serializes a cursor value as base64 JSON. The value is first converted to
+// the form the driver binds, so it compares against stored values the way the stored
+// values were written: times use the driver's text layout and blobs stay blobs.
+func encodeCursor(value any) (string, error) {
+	value, err := driver.DefaultParameterConverter.ConvertValue(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	token := cursorToken{Value: value}
+	switch v := value.(type) {
+	case time.Time:
+		token = cursorToken{Kind: "time", Value: formatDriverTime(v)}
+	case []byte:
+		token.Kind = "blob"
+	}
+
+	raw, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// decodeCursor reverses encodeCursor, keeping integers as int64 rather than float64,
+// blobs as []byte and times as the text the driver stores
+func decodeCursor(token string) (any, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	var tagged struct {
+		Kind  string          `json:"k"`
+		Value json.RawMessage `json:"v"`
+	}
+	if err := json.Unmarshal(raw, &tagged); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	switch tagged.Kind {
+	case "time":
+		var text string
+		if err := json.Unmarshal(tagged.Value, &text); err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		return text, nil
+	case "blob":
+		var blob []byte
+		if err := json.Unmarshal(tagged.Value, &blob); err != nil {
+			return nil, fmt.Errorf("invalid cursor: %w", err)
+		}
+		return blob, nil
+	case "":
+	default:
+		return nil, fmt.Errorf("invalid cursor: unknown kind %q", tagged.Kind)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(tagged.Value))
+	dec.UseNumber()
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		return n.Float64()
+	}
+	return value, nil
+}
+
+// formatDriverTime formats t the way modernc.org/sqlite writes a time.Time parameter by
+// default, which is time.Time.String without the monotonic clock reading
+func formatDriverTime(t time.Time) string {
+	return t.Round(0).String()
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	stamp := time.Date(2024, 1, 1, 10, 0, 0, 500, time.UTC)
+	tests := []struct {
+		name  string
+		value any
+		want  any
+	}{
+		{name: "integer", value: 42, want: int64(42)},
+		{name: "float", value: 1.5, want: 1.5},
+		{name: "string", value: "abc", want: "abc"},
+		{name: "timestamp", value: stamp, want: "2024-01-01 10:00:00.0000005 +0000 UTC"},
+		{name: "timestamp in another zone", value: stamp.In(time.FixedZone("CET", 3600)), want: "2024-01-01 11:00:00.0000005 +0100 CET"},
+		{name: "blob", value: []byte{0x00, 0xff, 'a'}, want: []byte{0x00, 0xff, 'a'}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := encodeCursor(tt.value)
+			if err != nil {
+				t.Fatalf("encodeCursor: %v", err)
+			}
+			got, err := decodeCursor(token)
+			if err != nil {
+				t.Fatalf("decodeCursor: %v", err)
+			}
+
+			switch w := tt.want.(type) {
+			case []byte:
+				if b, ok := got.([]byte); !ok || !bytes.Equal(b, w) {
+					t.Errorf("decoded %#v, want %#v", got, w)
+				}
+			default:
+				if got != w {
+					t.Errorf("decoded %#v, want %#v", got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestPaginateTimestampAndBlobCursors(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t, testConfig(t))
+
+	if _, err := db.Exec(ctx, "setup", "CREATE TABLE logs (created_at DATETIME PRIMARY KEY, tag BLOB UNIQUE, message TEXT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	// Same day, so a cursor compared in the wrong layout skips the later rows
+	base := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	const total = 7
+	for i := range total {
+		_, err := db.Exec(ctx, "insert", "INSERT INTO logs (created_at, tag, message) VALUES (?, ?, ?)",
+			base.Add(time.Duration(i)*time.Hour), []byte{byte(i)}, "entry")
+		if err != nil {
+			t.Fatalf("insert: %v", err)
+		}
+	}
+
+	type logEntry struct {
+		CreatedAt time.Time `db:"created_at"`
+		Tag       []byte    `db:"tag"`
+		Message   string    `db:"message"`
+	}
+
+	tests := []struct {
+		name   string
+		column string
+	}{
+		{name: "timestamp", column: "created_at"},
+		{name: "blob", column: "tag"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := PageRequest{Query: "SELECT created_at, tag, message FROM logs", CursorColumn: tt.column, PageSize: 2}
+			seen := 0
+			for pages := 0; ; pages++ {
+				if pages > total {
+					t.Fatal("pagination did not terminate")
+				}
+				page, err := Paginate[logEntry](ctx, db, req)
+				if err != nil {
+					t.Fatalf("Paginate: %v", err)
+				}
+				for _, item := range page.Items {
+					if want := base.Add(time.Duration(seen) * time.Hour); !item.CreatedAt.Equal(want) {
+						t.Fatalf("row %d created at %v, want %v", seen, item.CreatedAt, want)
+					}
+					seen++
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				req.Cursor = page.NextCursor
+			}
+			if seen != total {
+				t.Errorf("walked %d rows, want %d", seen, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Pool advisory thresholds: waits must exceed poolWaitRatio of queries for
+// poolWaitIntervals collection intervals in a row before the advice is logged, and it
+// is repeated at most once per poolAdviceInterval
+const (
+	poolWaitRatio      = 0.1
+	poolWaitIntervals  = 3
+	poolAdviceInterval = 30 * time.Minute
+)
+
+// poolAdvisor watches connection waits across metric intervals and decides when to
+// suggest a bigger pool. It is advisory only and never resizes the pool.
+type poolAdvisor struct {
+	lastWaits   int64     // WaitCount at the previous interval
+	lastQueries uint64    // Query count at the previous interval
+	streak      int       // Consecutive intervals over poolWaitRatio
+	lastAdvised time.Time // When the advice was last logged
+}
+
+// observe records an interval and reports whether to log the advice, along with the
+// share of queries that waited
+func (a *poolAdvisor) observe(stats sql.DBStats, queries uint64, now time.Time) (bool, float64) {
+	waits := stats.WaitCount - a.lastWaits
+	ran := queries - a.lastQueries
+	a.lastWaits, a.lastQueries = stats.WaitCount, queries
+
+	if ran == 0 {
+		a.streak = 0
+		return false, 0
+	}
+
+	ratio := float64(waits) / float64(ran)
+	if ratio < poolWaitRatio {
+		a.streak = 0
+		return false, ratio
+	}
+
+	a.streak++
+	if a.streak < poolWaitIntervals || now.Sub(a.lastAdvised) < poolAdviceInterval {
+		return false, ratio
+	}
+	a.lastAdvised = now
+	return true, ratio
+}
+
+// advisePoolSize logs a recommendation when the pool has been exhausted consistently
+func (d *LibSQLDatabase) advisePoolSize(advisor *poolAdvisor) {
+	stats := d.db.Stats()
+	advise, ratio := advisor.observe(stats, d.queries.Load(), time.Now())
+	// The auto-tuner handles waits itself until it reaches MaxOpenConns
+	if !advise || d.PoolTarget() < d.config.MaxOpenConns {
+		return
+	}
+
+	d.logger.Warn("connection pool frequently exhausted, consider raising MaxOpenConns",
+		"wait_ratio", ratio,
+		"max_open_conns", stats.MaxOpenConnections,
+		"in_use", stats.InUse,
+		"wait_duration", stats.WaitDuration,
+	)
+}
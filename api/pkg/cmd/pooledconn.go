@@ -0,0 +1,193 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"math/rand/v2"
+	"time"
+)
+
+// pooledConn wraps every driver connection the pool opens. It enforces the jittered
+// lifetime and StatementTimeout, tracks the rows and statements it hands out and evicts
+// itself after a failover. database/sql checks IsValid when a connection is returned to
+// the pool and ResetSession before it is reused, so expired connections are discarded
+// without serving another query.
+type pooledConn struct {
+	driver.Conn
+	expires     time.Time        // Zero when lifetime jitter is disabled
+	stmtTimeout time.Duration    // Deadline applied to every statement; zero disables
+	open        *openResources   // Shared counts of unclosed rows and statements
+	connector   *pragmaConnector // Receives failover notifications
+	broken      bool             // Set after a failover error; the pool must discard it
+	generation  int64            // Connector generation when opened
+}
+
+// newPooledConn wraps conn with the connector's settings, expiring it after the
+// lifetime ± a random share of the jitter
+func newPooledConn(conn driver.Conn, connector *pragmaConnector) *pooledConn {
+	c := &pooledConn{
+		Conn:        conn,
+		stmtTimeout: connector.stmtTimeout,
+		open:        &connector.open,
+		connector:   connector,
+		generation:  connector.generation.Load(),
+	}
+	lifetime, jitter := connector.lifetime, connector.jitter
+	if jitter > 0 && lifetime > 0 {
+		offset := time.Duration(rand.Int64N(int64(2*jitter)+1)) - jitter
+		c.expires = time.Now().Add(lifetime + offset)
+	}
+	return c
+}
+
+// expired reports whether the connection has outlived its jittered lifetime or was
+// opened before the connector's current generation
+func (c *pooledConn) expired() bool {
+	if c.generation < c.connector.generation.Load() {
+		return true
+	}
+	return !c.expires.IsZero() && time.Now().After(c.expires)
+}
+
+// IsValid reports false once the connection has expired or hit a failover so the pool
+// closes it
+func (c *pooledConn) IsValid() bool {
+	if c.expired() || c.broken {
+		return false
+	}
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+// ResetSession rejects expired and failed-over connections before they are reused
+func (c *pooledConn) ResetSession(ctx context.Context) error {
+	if c.expired() || c.broken {
+		return driver.ErrBadConn
+	}
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+// The wrapper hides the driver's optional interfaces, so forward the ones
+// database/sql relies on, falling back the way database/sql itself would
+
+// ExecContext forwards to the driver under the statement timeout, or asks
+// database/sql to prepare instead
+func (c *pooledConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, cancel := c.statementContext(ctx)
+	defer cancel()
+	result, err := e.ExecContext(ctx, query, args)
+	if err != nil {
+		return nil, c.failover(ctx, err)
+	}
+	return result, nil
+}
+
+// QueryContext forwards to the driver under the statement timeout, tracking the rows
+func (c *pooledConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	// The deadline has to outlive this call to cover iteration, so the rows release it
+	ctx, cancel := c.statementContext(ctx)
+	rows, err := q.QueryContext(ctx, query, args)
+	if err != nil {
+		cancel()
+		return nil, c.failover(ctx, err)
+	}
+
+	return c.trackRows(ctx, rows, cancel), nil
+}
+
+// trackRows wraps rows so they are counted while open, report their row count when the
+// context asks for it, and release the statement timeout on Close
+func (c *pooledConn) trackRows(ctx context.Context, rows driver.Rows, cancel context.CancelFunc) driver.Rows {
+	observe, _ := ctx.Value(rowsObserverKey{}).(func(int))
+	if release, ok := ctx.Value(rowsReleaseKey{}).(context.CancelFunc); ok {
+		stmtCancel := cancel
+		cancel = func() {
+			stmtCancel()
+			release()
+		}
+	}
+	c.open.rows.Add(1)
+	return &trackedRows{Rows: rows, observe: observe, cancel: cancel, open: &c.open.rows}
+}
+
+// statementContext applies StatementTimeout to ctx. The drivers in use don't expose
+// sqlite3_progress_handler, so the timeout relies on them interrupting the statement
+// when its context ends: modernc.org/sqlite calls sqlite3_interrupt and remote libSQL
+// aborts the request.
+func (c *pooledConn) statementContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.stmtTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.stmtTimeout)
+}
+
+// Prepare forwards to PrepareContext so legacy callers are tracked too
+func (c *pooledConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext forwards to the driver, tracking the statement until it is closed
+func (c *pooledConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, c.failover(ctx, err)
+	}
+
+	c.open.stmts.Add(1)
+	return &trackedStmt{Stmt: stmt, conn: c}, nil
+}
+
+// BeginTx forwards to the driver
+func (c *pooledConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	var tx driver.Tx
+	var err error
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else {
+		// Fallback for drivers without ConnBeginTx
+		tx, err = c.Conn.Begin()
+	}
+	if err != nil {
+		return nil, c.failover(ctx, err)
+	}
+	return tx, nil
+}
+
+// Ping forwards to the driver
+func (c *pooledConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		if err := p.Ping(ctx); err != nil {
+			return c.failover(ctx, err)
+		}
+	}
+	return nil
+}
+
+// CheckNamedValue forwards to the driver, or defers to the default conversion
+func (c *pooledConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
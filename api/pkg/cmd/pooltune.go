@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// poolTuneInterval is how often the auto-tuner reconsiders the pool size
+const poolTuneInterval = 30 * time.Second
+
+// poolTuneWait is the wait time per interval above which the auto-tuner grows the pool
+const poolTuneWait = 100 * time.Millisecond
+
+// poolTuneLoop steps the open connection limit between MinOpenConns and MaxOpenConns.
+// It grows by a quarter when callers spent more than poolTuneWait waiting for a
+// connection during the interval, and shrinks by one when no one waited and at most
+// half the connections were in use. It starts at MaxOpenConns so startup traffic is
+// never throttled.
+func (d *LibSQLDatabase) poolTuneLoop(ctx context.Context) {
+	ticker := time.NewTicker(poolTuneInterval)
+	defer ticker.Stop()
+
+	lastWait := d.db.Stats().WaitDuration
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats := d.db.Stats()
+			waited := stats.WaitDuration - lastWait
+			lastWait = stats.WaitDuration
+
+			target := d.PoolTarget()
+			next := target
+			switch {
+			case waited > poolTuneWait:
+				next = min(target+max(target/4, 1), d.config.MaxOpenConns)
+			case waited == 0 && stats.InUse <= target/2:
+				next = max(target-1, d.config.MinOpenConns)
+			}
+			if next == target {
+				continue
+			}
+
+			d.setPoolTarget(next)
+			d.logger.Debug("pool size adjusted",
+				"from", target,
+				"to", next,
+				"waited", waited,
+				"in_use", stats.InUse,
+			)
+		}
+	}
+}
+
+// setPoolTarget applies a new open connection limit. Lowering the limit also lowers the
+// idle limit, so it is restored from config when the pool grows again.
+func (d *LibSQLDatabase) setPoolTarget(target int) {
+	d.poolTarget.Store(int64(target))
+	d.db.SetMaxOpenConns(target)
+	d.db.SetMaxIdleConns(min(d.config.MaxIdleConns, target))
+}
+
+// PoolTarget returns the open connection limit currently in force. Without AutoTunePool
+// it is always MaxOpenConns.
+func (d *LibSQLDatabase) PoolTarget() int {
+	return int(d.poolTarget.Load())
+}
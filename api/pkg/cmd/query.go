@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Query runs a query and records its duration and outcome under queryType
+func (d *LibSQLDatabase) Query(ctx context.Context, queryType, query string, args ...any) (*sql.Rows, error) {
+	if err := d.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	d.syncForRead(ctx)
+	ctx, span := d.startSpan(ctx, "db.query", query)
+
+	// The deadline must cover iteration, so it is released when the rows are closed
+	// rather than when this call returns
+	ctx, cancel := d.queryContext(ctx)
+	ctx = withRowsRelease(d.countRows(ctx, queryType), cancel)
+
+	start := time.Now()
+	rows, err := d.db.QueryContext(ctx, query, args...)
+	d.observeQuery(ctx, queryType, query, time.Since(start), err)
+	if err != nil {
+		cancel()
+	}
+
+	endSpan(span, err)
+	return rows, err
+}
+
+// QueryRow runs a single-row query and records its duration and outcome under queryType.
+// sql.Row can't carry a custom error, so QueryRow is not gated by the circuit breaker.
+func (d *LibSQLDatabase) QueryRow(ctx context.Context, queryType, query string, args ...any) *sql.Row {
+	d.syncForRead(ctx)
+	ctx, span := d.startSpan(ctx, "db.query", query)
+
+	// Scan happens after return, so the deadline is released when Scan closes the rows
+	ctx, cancel := d.queryContext(ctx)
+	ctx = withRowsRelease(ctx, cancel)
+
+	start := time.Now()
+	row := d.db.QueryRowContext(ctx, query, args...)
+	d.observeQuery(ctx, queryType, query, time.Since(start), row.Err())
+	if row.Err() != nil {
+		cancel()
+	}
+
+	endSpan(span, row.Err())
+	return row
+}
+
+// ExecContext runs a statement and records its duration and outcome under queryType
+func (d *LibSQLDatabase) ExecContext(ctx context.Context, queryType, query string, args ...any) (sql.Result, error) {
+	if err := d.breaker.allow(); err != nil {
+		return nil, err
+	}
+	if err := d.allowWrite(); err != nil {
+		return nil, err
+	}
+
+	ctx, span := d.startSpan(ctx, "db.exec", query)
+
+	ctx, cancel := d.queryContext(ctx)
+	defer cancel()
+
+	start := time.Now()
+	result, err := d.db.ExecContext(ctx, query, args...)
+	d.observeQuery(ctx, queryType, query, time.Since(start), err)
+	d.recordWrite(err)
+	if err == nil {
+		markWrite(ctx)
+	}
+
+	if err == nil && (span != nil || d.metrics != nil || d.rowCounts.tracking()) {
+		if affected, raErr := result.RowsAffected(); raErr == nil {
+			d.rowCounts.adjust(query, affected)
+			if span != nil {
+				span.SetAttributes(attribute.Int64("db.rows_affected", affected))
+			}
+			if d.metrics != nil {
+				d.metrics.rowsAffected.WithLabelValues(d.queryLabel(queryType)).Observe(float64(affected))
+			}
+		}
+	}
+	endSpan(span, err)
+	return result, err
+}
+
+// countRows records the number of rows a query on ctx returns once its rows are closed
+func (d *LibSQLDatabase) countRows(ctx context.Context, queryType string) context.Context {
+	if d.metrics == nil {
+		return ctx
+	}
+	label := d.queryLabel(queryType)
+	return withRowsObserver(ctx, func(n int) {
+		d.metrics.rowsReturned.WithLabelValues(label).Observe(float64(n))
+	})
+}
+
+// Exec runs a statement like ExecContext and returns the number of rows it affected.
+// Constraint violations are wrapped with ErrConstraint so callers can test with errors.Is.
+func (d *LibSQLDatabase) Exec(ctx context.Context, queryType, query string, args ...any) (int64, error) {
+	result, err := d.ExecContext(ctx, queryType, query, args...)
+	if err != nil {
+		if IsConstraintViolation(err) {
+			return 0, fmt.Errorf("%w: %w", ErrConstraint, err)
+		}
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rows affected: %w", err)
+	}
+	return affected, nil
+}
+
+// queryContext applies MaxQueryDuration to ctx. A caller deadline that is already
+// earlier wins, since context deadlines only ever shorten.
+func (d *LibSQLDatabase) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if d.config.MaxQueryDuration <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d.config.MaxQueryDuration)
+}
+
+// Stream runs query and hands the open rows to fn so large results can be processed
+// incrementally. The rows are always closed, and iteration errors surface after fn returns.
+func (d *LibSQLDatabase) Stream(ctx context.Context, fn func(*sql.Rows) error, query string, args ...any) (err error) {
+	rows, err := d.Query(ctx, "stream", query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := rows.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if err := fn(rows); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read streamed rows: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package database
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+)
+
+// QueryJSON streams query's result set to w as a JSON array of objects keyed by column
+// name, in column order. Rows are written as they are read, so large results aren't held
+// in memory. SQLite values map to JSON by their storage class: integers and reals become
+// numbers (NaN and infinities become null), text becomes a string, blobs become base64
+// strings and NULL becomes null. If the query fails partway, w holds a truncated array.
+func (d *LibSQLDatabase) QueryJSON(ctx context.Context, w io.Writer, query string, args ...any) error {
+	bw := bufio.NewWriter(w)
+
+	err := d.Stream(ctx, func(rows *sql.Rows) error {
+		columns, err := rows.Columns()
+		if err != nil {
+			return fmt.Errorf("failed to read columns: %w", err)
+		}
+
+		// Encode the keys once: `"name":` with a leading comma after the first
+		keys := make([][]byte, len(columns))
+		for i, column := range columns {
+			key, err := json.Marshal(column)
+			if err != nil {
+				return fmt.Errorf("failed to encode column %q: %w", column, err)
+			}
+			if i > 0 {
+				key = append([]byte{','}, key...)
+			}
+			keys[i] = append(key, ':')
+		}
+
+		values := make([]any, len(columns))
+		dest := make([]any, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+
+		bw.WriteByte('[')
+		for n := 0; rows.Next(); n++ {
+			if err := rows.Scan(dest...); err != nil {
+				return fmt.Errorf("failed to scan row: %w", err)
+			}
+
+			if n > 0 {
+				bw.WriteByte(',')
+			}
+			bw.WriteByte('{')
+			for i, v := range values {
+				encoded, err := json.Marshal(jsonValue(v))
+				if err != nil {
+					return fmt.Errorf("failed to encode column %q: %w", columns[i], err)
+				}
+				bw.Write(keys[i])
+				bw.Write(encoded)
+			}
+			if _, err := bw.WriteString("}"); err != nil {
+				return fmt.Errorf("failed to write JSON row: %w", err)
+			}
+		}
+		bw.WriteByte(']')
+		return nil
+	}, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export JSON: %w", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to flush JSON: %w", err)
+	}
+	return nil
+}
+
+// jsonValue adapts a scanned SQLite value for encoding/json, which already encodes
+// []byte as base64 and nil as null
+func jsonValue(v any) any {
+	if f, ok := v.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,98 @@
+package database
+
+import "sync"
+
+// otherQueryType is the metric label used for query types that were never registered
+const otherQueryType = "other"
+
+// maxWarnedQueryTypes bounds how many distinct unregistered types are remembered for
+// the one-time warning, since the point is to survive callers passing dynamic values
+const maxWarnedQueryTypes = 1000
+
+// builtinQueryTypes are the query types used by this package's own helpers
+var builtinQueryTypes = []string{
+	"batch_exec",
+	"bulk_insert",
+	"cached_query",
+	"commit",
+	"ensure_index",
+	"exec_batch",
+	"explain_query_plan",
+	"fts_search",
+	"import_csv",
+	"json_extract",
+	"json_set",
+	maintenanceQueryType,
+	"named_exec",
+	"named_query",
+	"paginate",
+	"query_row_struct",
+	"row_count",
+	"soft_delete",
+	"stream",
+	"transaction",
+	"upsert",
+	"vector_search",
+}
+
+// queryTypeRegistry is the allowlist of query types that may become metric labels
+type queryTypeRegistry struct {
+	mu     sync.RWMutex
+	known  map[string]bool
+	warned map[string]bool
+}
+
+// newQueryTypeRegistry returns a registry seeded with the built-in query types
+func newQueryTypeRegistry() *queryTypeRegistry {
+	r := &queryTypeRegistry{
+		known:  make(map[string]bool, len(builtinQueryTypes)),
+		warned: make(map[string]bool),
+	}
+	for _, name := range builtinQueryTypes {
+		r.known[name] = true
+	}
+	return r
+}
+
+// RegisterQueryType adds query types to the metrics allowlist. Call it at startup for
+// every queryType passed to Query, ExecContext, ObserveQuery and friends; anything
+// unregistered is recorded under "other" to keep label cardinality bounded.
+func (d *LibSQLDatabase) RegisterQueryType(names ...string) {
+	d.queryTypes.mu.Lock()
+	defer d.queryTypes.mu.Unlock()
+
+	for _, name := range names {
+		d.queryTypes.known[name] = true
+	}
+}
+
+// queryLabel returns queryType if registered and "other" otherwise, warning the first
+// time each unregistered type is seen
+func (d *LibSQLDatabase) queryLabel(queryType string) string {
+	r := d.queryTypes
+
+	r.mu.RLock()
+	known, warned := r.known[queryType], r.warned[queryType]
+	r.mu.RUnlock()
+
+	if known {
+		return queryType
+	}
+	if warned {
+		return otherQueryType
+	}
+
+	r.mu.Lock()
+	first := !r.warned[queryType] && len(r.warned) < maxWarnedQueryTypes
+	if first {
+		r.warned[queryType] = true
+	}
+	r.mu.Unlock()
+
+	if first {
+		d.logger.Warn("unregistered query type recorded as other, call RegisterQueryType",
+			"query_type", queryType,
+		)
+	}
+	return otherQueryType
+}
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync/atomic"
+)
+
+// readYourWritesKey holds the *readYourWrites state of a request scope
+type readYourWritesKey struct{}
+
+// readYourWrites tracks writes made within a request scope
+type readYourWrites struct {
+	wrote    atomic.Bool // Any write succeeded in this scope
+	unsynced atomic.Bool // A write succeeded since the embedded replica last synced
+}
+
+// WithReadYourWrites marks ctx as a request scope whose reads must see its own writes.
+// After a write through ExecContext, Exec or Transaction on ctx:
+//
+//   - with an embedded replica, the next Query or QueryRow syncs the replica first, so
+//     the read pays a round trip to the primary. Later reads skip the sync until
+//     another write.
+//   - ReadDBContext routes to the primary instead of a read replica for the rest of
+//     the scope, giving up replica offload for those reads.
+//
+// Scopes without writes pay nothing. A failed sync is logged and the read proceeds
+// against the possibly stale replica.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, readYourWritesKey{}, &readYourWrites{})
+}
+
+// markWrite records a successful write for ctx's read-your-writes scope, if any
+func markWrite(ctx context.Context) {
+	if state, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok {
+		state.wrote.Store(true)
+		state.unsynced.Store(true)
+	}
+}
+
+// syncForRead syncs the embedded replica when ctx's scope has unsynced writes
+func (d *LibSQLDatabase) syncForRead(ctx context.Context) {
+	state, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites)
+	if !ok || !isEmbeddedReplica(d.config) || !state.unsynced.Swap(false) {
+		return
+	}
+
+	if err := d.Sync(ctx); err != nil {
+		state.unsynced.Store(true)
+		d.logger.Warn("read-your-writes sync failed, reading from a possibly stale replica",
+			append([]any{"error", err}, logTags(ctx)...)...)
+	}
+}
+
+// ReadDBContext is ReadDB for a request scope: once ctx's read-your-writes scope has
+// written, it returns the primary so reads can't hit a lagging replica
+func (d *LibSQLDatabase) ReadDBContext(ctx context.Context) *sql.DB {
+	if state, ok := ctx.Value(readYourWritesKey{}).(*readYourWrites); ok && state.wrote.Load() {
+		return d.db
+	}
+	return d.ReadDB()
+}
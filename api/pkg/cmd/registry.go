@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DatabaseRegistry holds named databases so their lifecycle is managed in one place
+type DatabaseRegistry struct {
+	mu  sync.RWMutex
+	dbs map[string]*LibSQLDatabase
+}
+
+// NewDatabaseRegistry returns an empty registry
+func NewDatabaseRegistry() *DatabaseRegistry {
+	return &DatabaseRegistry{dbs: make(map[string]*LibSQLDatabase)}
+}
+
+// Register adds db under name, replacing any database already registered there
+func (r *DatabaseRegistry) Register(name string, db *LibSQLDatabase) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dbs[name] = db
+}
+
+// Get returns the database registered under name, or ErrUnknownDatabase
+func (r *DatabaseRegistry) Get(name string) (*LibSQLDatabase, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	db, ok := r.dbs[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownDatabase, name)
+	}
+	return db, nil
+}
+
+// Names returns the registered names in sorted order
+func (r *DatabaseRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.dbs))
+	for name := range r.dbs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Health runs Readiness on every registered database concurrently and joins the
+// failures, each prefixed with its database name
+func (r *DatabaseRegistry) Health(ctx context.Context) error {
+	return r.each(func(name string, db *LibSQLDatabase) error {
+		return db.Readiness(ctx)
+	})
+}
+
+// CloseAll closes every registered database concurrently and empties the registry.
+// It returns the joined close errors, or ctx's error if ctx ends first; closes that
+// are still running then finish in the background.
+func (r *DatabaseRegistry) CloseAll(ctx context.Context) error {
+	r.mu.Lock()
+	dbs := r.dbs
+	r.dbs = make(map[string]*LibSQLDatabase)
+	r.mu.Unlock()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- eachDatabase(dbs, func(name string, db *LibSQLDatabase) error {
+			return db.Close()
+		})
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("failed to close all databases: %w", ctx.Err())
+	}
+}
+
+// each runs fn for every registered database concurrently
+func (r *DatabaseRegistry) each(fn func(string, *LibSQLDatabase) error) error {
+	r.mu.RLock()
+	dbs := make(map[string]*LibSQLDatabase, len(r.dbs))
+	for name, db := range r.dbs {
+		dbs[name] = db
+	}
+	r.mu.RUnlock()
+
+	return eachDatabase(dbs, fn)
+}
+
+// eachDatabase runs fn for every database in dbs concurrently and joins the errors
+func eachDatabase(dbs map[string]*LibSQLDatabase, fn func(string, *LibSQLDatabase) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for name, db := range dbs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(name, db); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,62 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ReadDB returns a read replica pool using round-robin selection,
+// falling back to the primary when no replicas are configured
+func (d *LibSQLDatabase) ReadDB() *sql.DB {
+	if len(d.replicas) == 0 {
+		return d.db
+	}
+
+	n := d.next.Add(1) - 1
+	return d.replicas[n%uint64(len(d.replicas))]
+}
+
+// openReplicas opens a connection pool for every configured replica URL
+func openReplicas(ctx context.Context, cfg LibSQLConfig) ([]*sql.DB, error) {
+	replicas := make([]*sql.DB, 0, len(cfg.ReplicaURLs))
+	for i, replicaURL := range cfg.ReplicaURLs {
+		replicaCfg := cfg
+		replicaCfg.URL = replicaURL
+		replicaCfg.SyncURL = ""
+
+		var db *sql.DB
+		connStr, err := buildConnStr(replicaCfg)
+		if err == nil {
+			db, _, err = openPool(ctx, replicaCfg, connStr)
+		}
+		if err != nil {
+			for _, opened := range replicas {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("failed to open read replica %d: %w", i, err)
+		}
+		replicas = append(replicas, db)
+	}
+
+	return replicas, nil
+}
+
+// replicaHealth succeeds if no replicas are configured or at least one responds
+func (d *LibSQLDatabase) replicaHealth(ctx context.Context) error {
+	if len(d.replicas) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, replica := range d.replicas {
+		err := replica.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+
+	return fmt.Errorf("all read replicas unreachable: %w", errors.Join(errs...))
+}
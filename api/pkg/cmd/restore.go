@@ -0,0 +1,383 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// sqliteHeader is the magic string at the start of every SQLite database file
+var sqliteHeader = []byte("SQLite format 3\x00")
+
+// virtualModulePattern captures the module name from a CREATE VIRTUAL TABLE statement
+var virtualModulePattern = regexp.MustCompile(`(?i)\bUSING\s+(\w+)`)
+
+// contentlessPattern matches the empty content option of a contentless full-text table
+var contentlessPattern = regexp.MustCompile(`(?i)\bcontent\s*=\s*(''|"")`)
+
+// schemaObject is a row from sqlite_master
+type schemaObject struct {
+	kind string
+	name string
+	sql  string
+}
+
+// RestoreFrom replaces the contents of a local database with those of the backup at srcPath.
+//
+// The restore runs through SQLite rather than copying files: the backup is attached,
+// an exclusive lock is taken, and every object is dropped and recreated inside a single
+// transaction. Any failure rolls back and leaves the original database intact, and
+// SQLite takes care of the WAL/SHM sidecar files of both databases.
+func (d *LibSQLDatabase) RestoreFrom(ctx context.Context, srcPath string) error {
+	if !isLocalFile(d.config.URL) {
+		return fmt.Errorf("restore is only supported for local file databases")
+	}
+
+	if err := checkSQLiteFile(srcPath); err != nil {
+		return err
+	}
+
+	srcAbs, err := filepath.Abs(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve restore source: %w", err)
+	}
+	dstAbs, err := filepath.Abs(localPath(d.config.URL))
+	if err != nil {
+		return fmt.Errorf("failed to resolve database path: %w", err)
+	}
+	if srcAbs == dstAbs {
+		return fmt.Errorf("cannot restore database from itself")
+	}
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for restore: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "ATTACH DATABASE ? AS restore_src", srcAbs); err != nil {
+		return fmt.Errorf("failed to attach backup: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "DETACH DATABASE restore_src"); err != nil {
+			d.logger.Warn("failed to detach backup after restore", "error", err)
+		}
+	}()
+
+	// Foreign keys must be off while tables are dropped and refilled in arbitrary order
+	if _, err := conn.ExecContext(ctx, "PRAGMA foreign_keys=OFF"); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "PRAGMA foreign_keys=ON"); err != nil {
+			d.logger.Warn("failed to re-enable foreign keys after restore", "error", err)
+		}
+	}()
+
+	// Exclusive lock keeps every other connection out until the restore commits
+	if _, err := conn.ExecContext(ctx, "BEGIN EXCLUSIVE"); err != nil {
+		return fmt.Errorf("failed to acquire exclusive lock: %w", err)
+	}
+
+	if err := replaceSchema(ctx, conn); err != nil {
+		if _, rbErr := conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK"); rbErr != nil {
+			d.logger.Error("failed to rollback restore", "error", rbErr)
+		}
+		return fmt.Errorf("failed to restore from %s: %w", srcPath, err)
+	}
+
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to commit restore: %w", err)
+	}
+
+	// Cached statements may reference objects that no longer exist
+	d.stmts.closeAll()
+	d.rowCounts.reset()
+
+	if _, err := conn.ExecContext(ctx, "PRAGMA wal_checkpoint(TRUNCATE)"); err != nil {
+		d.logger.Warn("failed to checkpoint after restore", "error", err)
+	}
+
+	d.logger.Info("database restored from backup", "path", srcPath)
+	return nil
+}
+
+// replaceSchema drops every object in main and recreates it with data from restore_src
+func replaceSchema(ctx context.Context, conn *sql.Conn) error {
+	existing, err := schemaObjects(ctx, conn, "main")
+	if err != nil {
+		return err
+	}
+
+	// Dropping a table also drops its indexes and triggers; drop views first
+	for _, kind := range []string{"view", "table"} {
+		for _, obj := range existing {
+			if obj.kind != kind {
+				continue
+			}
+			if _, err := conn.ExecContext(ctx, fmt.Sprintf("DROP %s IF EXISTS main.%s", kind, quoteIdent(obj.name))); err != nil {
+				return fmt.Errorf("failed to drop %s %s: %w", kind, obj.name, err)
+			}
+		}
+	}
+
+	source, err := schemaObjects(ctx, conn, "restore_src")
+	if err != nil {
+		return err
+	}
+
+	tables, err := listTables(ctx, conn, "restore_src")
+	if err != nil {
+		return err
+	}
+
+	// Create and fill tables before indexes, triggers and views that depend on them.
+	// Virtual tables that can rebuild themselves do so once all content is in place.
+	var rebuild []string
+	for _, obj := range source {
+		table := tables[obj.name]
+		if obj.kind != "table" || table.kind == "shadow" {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, obj.sql); err != nil {
+			return fmt.Errorf("failed to create table %s: %w", obj.name, err)
+		}
+
+		if table.kind != "virtual" {
+			if err := copyTable(ctx, conn, obj.name, !table.withoutRowID); err != nil {
+				return err
+			}
+			continue
+		}
+		rebuilds, err := copyVirtualTable(ctx, conn, obj, tables)
+		if err != nil {
+			return err
+		}
+		if rebuilds {
+			rebuild = append(rebuild, obj.name)
+		}
+	}
+	for _, name := range rebuild {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("INSERT INTO main.%[1]s(%[1]s) VALUES ('rebuild')", quoteIdent(name))); err != nil {
+			return fmt.Errorf("failed to rebuild %s: %w", name, err)
+		}
+	}
+
+	for _, kind := range []string{"index", "trigger", "view"} {
+		for _, obj := range source {
+			if obj.kind != kind {
+				continue
+			}
+			if _, err := conn.ExecContext(ctx, obj.sql); err != nil {
+				return fmt.Errorf("failed to create %s %s: %w", kind, obj.name, err)
+			}
+		}
+	}
+
+	// Preserve AUTOINCREMENT counters and the application's user_version
+	var hasSequence int
+	err = conn.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM restore_src.sqlite_master WHERE name = 'sqlite_sequence'",
+	).Scan(&hasSequence)
+	if err != nil {
+		return fmt.Errorf("failed to inspect sqlite_sequence: %w", err)
+	}
+	if hasSequence > 0 {
+		if _, err := conn.ExecContext(ctx, "DELETE FROM main.sqlite_sequence"); err != nil {
+			return fmt.Errorf("failed to reset sqlite_sequence: %w", err)
+		}
+		if _, err := conn.ExecContext(ctx, "INSERT INTO main.sqlite_sequence SELECT * FROM restore_src.sqlite_sequence"); err != nil {
+			return fmt.Errorf("failed to copy sqlite_sequence: %w", err)
+		}
+	}
+
+	var userVersion int
+	if err := conn.QueryRowContext(ctx, "PRAGMA restore_src.user_version").Scan(&userVersion); err != nil {
+		return fmt.Errorf("failed to read user_version: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, fmt.Sprintf("PRAGMA main.user_version=%d", userVersion)); err != nil {
+		return fmt.Errorf("failed to set user_version: %w", err)
+	}
+
+	return nil
+}
+
+// schemaObjects lists user-defined objects in the given schema's sqlite_master
+func schemaObjects(ctx context.Context, conn *sql.Conn, schema string) ([]schemaObject, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf(
+		"SELECT type, name, sql FROM %s.sqlite_master WHERE sql IS NOT NULL AND name NOT LIKE 'sqlite_%%' ORDER BY rowid",
+		schema,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s schema: %w", schema, err)
+	}
+	defer rows.Close()
+
+	var objects []schemaObject
+	for rows.Next() {
+		var obj schemaObject
+		if err := rows.Scan(&obj.kind, &obj.name, &obj.sql); err != nil {
+			return nil, fmt.Errorf("failed to scan %s schema: %w", schema, err)
+		}
+		objects = append(objects, obj)
+	}
+
+	return objects, rows.Err()
+}
+
+// tableListEntry is a row from PRAGMA table_list
+type tableListEntry struct {
+	kind         string // table, view, virtual or shadow
+	withoutRowID bool
+}
+
+// listTables returns the tables in schema by name, including the internal tables
+// backing virtual tables such as FTS5, which SQLite recreates itself when the virtual
+// table is created
+func listTables(ctx context.Context, conn *sql.Conn, schema string) (map[string]tableListEntry, error) {
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("PRAGMA %s.table_list", schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s tables: %w", schema, err)
+	}
+	defer rows.Close()
+
+	tables := make(map[string]tableListEntry)
+	for rows.Next() {
+		var tableSchema, name, kind string
+		var ncol, withoutRowID, strict int
+		if err := rows.Scan(&tableSchema, &name, &kind, &ncol, &withoutRowID, &strict); err != nil {
+			return nil, fmt.Errorf("failed to scan %s tables: %w", schema, err)
+		}
+		tables[name] = tableListEntry{kind: kind, withoutRowID: withoutRowID != 0}
+	}
+
+	return tables, rows.Err()
+}
+
+// copyTable copies every row of restore_src.name into main.name. Columns are listed
+// explicitly so generated columns, which can't be written, are skipped, and withRowID
+// carries implicit rowids over so they aren't renumbered.
+func copyTable(ctx context.Context, conn *sql.Conn, name string, withRowID bool) error {
+	columns, err := copyColumns(ctx, conn, name, withRowID)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+
+	list := strings.Join(columns, ", ")
+	copySQL := fmt.Sprintf("INSERT INTO main.%[1]s (%[2]s) SELECT %[2]s FROM restore_src.%[1]s", quoteIdent(name), list)
+	if _, err := conn.ExecContext(ctx, copySQL); err != nil {
+		return fmt.Errorf("failed to copy table %s: %w", name, err)
+	}
+	return nil
+}
+
+// copyColumns lists the quoted columns of restore_src.name that can be written:
+// hidden and generated columns are left out. With withRowID, the rowid comes first
+// unless an INTEGER PRIMARY KEY column already aliases it.
+func copyColumns(ctx context.Context, conn *sql.Conn, name string, withRowID bool) ([]string, error) {
+	rows, err := conn.QueryContext(ctx,
+		"SELECT name, type, pk, hidden FROM pragma_table_xinfo(?, 'restore_src')", name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	taken := make(map[string]bool)
+	pkColumns, integerPK := 0, false
+	for rows.Next() {
+		var column, colType string
+		var pk, hidden int
+		if err := rows.Scan(&column, &colType, &pk, &hidden); err != nil {
+			return nil, fmt.Errorf("failed to scan columns of %s: %w", name, err)
+		}
+		taken[strings.ToLower(column)] = true
+		if pk > 0 {
+			pkColumns++
+			integerPK = strings.EqualFold(colType, "INTEGER")
+		}
+		// 1 is a hidden virtual table column, 2 and 3 are generated columns
+		if hidden == 0 {
+			columns = append(columns, quoteIdent(column))
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", name, err)
+	}
+
+	if !withRowID || pkColumns == 1 && integerPK {
+		return columns, nil
+	}
+	// A column may shadow one of the rowid's names; any unshadowed alias works
+	for _, alias := range []string{"rowid", "_rowid_", "oid"} {
+		if !taken[alias] {
+			return append([]string{alias}, columns...), nil
+		}
+	}
+	return columns, nil
+}
+
+// copyVirtualTable fills a freshly created virtual table from restore_src and reports
+// whether it must be rebuilt afterwards. Full-text tables get only their backing
+// content: an internal _content table is copied, an external content table is copied
+// as a regular table, and the index is rebuilt from it. Contentless full-text tables
+// hold nothing but the index, so their shadow tables are copied verbatim. Other modules
+// are copied row by row through the virtual table, rowids included.
+func copyVirtualTable(ctx context.Context, conn *sql.Conn, obj schemaObject, tables map[string]tableListEntry) (bool, error) {
+	match := virtualModulePattern.FindStringSubmatch(obj.sql)
+	if match == nil || !strings.HasPrefix(strings.ToLower(match[1]), "fts") {
+		return false, copyTable(ctx, conn, obj.name, true)
+	}
+
+	if !contentlessPattern.MatchString(obj.sql) {
+		content := obj.name + "_content"
+		if tables[content].kind == "shadow" {
+			if err := copyTable(ctx, conn, content, true); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+
+	for name, table := range tables {
+		if table.kind != "shadow" || !strings.HasPrefix(name, obj.name+"_") {
+			continue
+		}
+		// Creating the virtual table seeded its shadow tables; replace those rows
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("DELETE FROM main.%s", quoteIdent(name))); err != nil {
+			return false, fmt.Errorf("failed to clear %s: %w", name, err)
+		}
+		if err := copyTable(ctx, conn, name, !table.withoutRowID); err != nil {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+// checkSQLiteFile verifies that path starts with the SQLite header magic
+func checkSQLiteFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open restore source: %w", err)
+	}
+	defer f.Close()
+
+	header := make([]byte, len(sqliteHeader))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return fmt.Errorf("restore source %s is not a SQLite database: %w", path, err)
+	}
+	if !bytes.Equal(header, sqliteHeader) {
+		return fmt.Errorf("restore source %s is not a SQLite database", path)
+	}
+
+	return nil
+}
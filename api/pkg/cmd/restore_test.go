@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+func TestRestoreFromKeepsRowIDs(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Written directly rather than with BackupTo: VACUUM may renumber implicit rowids
+	srcCfg := testConfig(t)
+	src, err := NewLibSQLDatabase(srcCfg, logger)
+	if err != nil {
+		t.Fatalf("open source: %v", err)
+	}
+	setup := []string{
+		"CREATE TABLE notes (body TEXT)",
+		"INSERT INTO notes (body) VALUES ('one'), ('two'), ('three'), ('four'), ('five')",
+		"DELETE FROM notes WHERE body IN ('two', 'four')",
+		"CREATE TABLE doubled (a INTEGER, b INTEGER GENERATED ALWAYS AS (a * 2) VIRTUAL)",
+		"INSERT INTO doubled (a) VALUES (1), (2)",
+	}
+	for _, stmt := range setup {
+		if _, err := src.Exec(ctx, "setup", stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+	if err := src.SetupFTS(ctx, "notes", []string{"body"}); err != nil {
+		t.Fatalf("SetupFTS: %v", err)
+	}
+	if err := src.Close(); err != nil {
+		t.Fatalf("close source: %v", err)
+	}
+
+	db := openTestDB(t, testConfig(t))
+	if _, err := db.Exec(ctx, "setup", "CREATE TABLE stale (id INTEGER)"); err != nil {
+		t.Fatalf("create stale table: %v", err)
+	}
+	if err := db.RestoreFrom(ctx, localPath(srcCfg.URL)); err != nil {
+		t.Fatalf("RestoreFrom: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "implicit rowids", query: "SELECT rowid || ':' || body FROM notes ORDER BY rowid", want: []string{"1:one", "3:three", "5:five"}},
+		{name: "generated columns", query: "SELECT a || ':' || b FROM doubled ORDER BY a", want: []string{"1:2", "2:4"}},
+		{name: "full-text index", query: "SELECT rowid FROM notes_fts WHERE notes_fts MATCH 'three OR five' ORDER BY rowid", want: []string{"3", "5"}},
+		{name: "old tables dropped", query: "SELECT name FROM sqlite_master WHERE name = 'stale'", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := db.Query(ctx, "check", tt.query)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			defer rows.Close()
+
+			var got []string
+			for rows.Next() {
+				var v string
+				if err := rows.Scan(&v); err != nil {
+					t.Fatalf("Scan: %v", err)
+				}
+				got = append(got, v)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatalf("rows: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
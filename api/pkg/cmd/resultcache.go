@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// resultCacheSweepSize is the entry count above which expired results are purged on insert
+const resultCacheSweepSize = 1024
+
+// resultCache holds scanned single-row query results with per-entry expiry
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]cachedResult
+}
+
+// cachedResult is a scanned row and its expiry time
+type cachedResult struct {
+	values  []any
+	expires time.Time
+}
+
+// newResultCache creates an empty result cache
+func newResultCache() *resultCache {
+	return &resultCache{entries: make(map[string]cachedResult)}
+}
+
+// CachedQueryRow scans a single row into dest, serving it from memory for ttl after the
+// first successful read. Use InvalidateCache after writes that affect the cached row.
+func (d *LibSQLDatabase) CachedQueryRow(ctx context.Context, ttl time.Duration, dest []any, query string, args ...any) error {
+	key := resultCacheKey(query, args)
+
+	if values, ok := d.results.get(key); ok {
+		d.observeQueryCache("hit")
+		return assignCached(dest, values)
+	}
+	d.observeQueryCache("miss")
+
+	if err := d.QueryRow(ctx, "cached_query", query, args...).Scan(dest...); err != nil {
+		return err
+	}
+
+	values := make([]any, len(dest))
+	for i, ptr := range dest {
+		values[i] = copyValue(reflect.ValueOf(ptr).Elem().Interface())
+	}
+	d.results.set(key, values, ttl)
+
+	return nil
+}
+
+// InvalidateCache drops the cached result for query and args
+func (d *LibSQLDatabase) InvalidateCache(query string, args ...any) {
+	d.results.delete(resultCacheKey(query, args))
+}
+
+// observeQueryCache records a result cache hit or miss
+func (d *LibSQLDatabase) observeQueryCache(result string) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.queryCache.WithLabelValues(result).Inc()
+}
+
+// resultCacheKey identifies a query and its arguments
+func resultCacheKey(query string, args []any) string {
+	return fmt.Sprintf("%s\x00%#v", query, args)
+}
+
+// assignCached copies cached values into the caller's destination pointers
+func assignCached(dest []any, values []any) error {
+	if len(dest) != len(values) {
+		return fmt.Errorf("cached row has %d columns but %d destinations were given", len(values), len(dest))
+	}
+
+	for i, ptr := range dest {
+		target := reflect.ValueOf(ptr)
+		if target.Kind() != reflect.Pointer || target.IsNil() {
+			return fmt.Errorf("destination %d is not a non-nil pointer", i)
+		}
+
+		value := reflect.ValueOf(copyValue(values[i]))
+		if !value.IsValid() {
+			target.Elem().Set(reflect.Zero(target.Elem().Type()))
+			continue
+		}
+		if !value.Type().AssignableTo(target.Elem().Type()) {
+			return fmt.Errorf("cached value %d of type %s cannot be assigned to %s", i, value.Type(), target.Elem().Type())
+		}
+		target.Elem().Set(value)
+	}
+
+	return nil
+}
+
+// copyValue copies byte slices so callers can't mutate cached data
+func copyValue(v any) any {
+	if b, ok := v.([]byte); ok {
+		return append([]byte(nil), b...)
+	}
+	return v
+}
+
+// get returns unexpired cached values for key
+func (c *resultCache) get(key string) ([]any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.values, true
+}
+
+// set caches values for key until ttl elapses
+func (c *resultCache) set(key string, values []any, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= resultCacheSweepSize {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+
+	c.entries[key] = cachedResult{values: values, expires: now.Add(ttl)}
+}
+
+// delete removes the cached values for key
+func (c *resultCache) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
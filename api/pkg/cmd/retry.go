@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"syscall"
+	"time"
+)
+
+// TransactionWithRetry runs Transaction, retrying failures that ErrorClassifier deems
+// retryable with exponential backoff
+func (d *LibSQLDatabase) TransactionWithRetry(ctx context.Context, fn func(*sql.Tx) error) error {
+	return d.retryTransaction(ctx, d.config.MaxRetries+1, d.retryable, fn)
+}
+
+// RetryableTransaction runs Transaction and, when it fails with a WAL snapshot conflict
+// (SQLITE_BUSY_SNAPSHOT), re-runs the whole of fn from scratch, up to maxAttempts times
+// in total with exponential backoff. Retrying only the commit can't fix a conflict because
+// fn's reads are stale, so fn must be safe to repeat: it may only touch the database
+// through tx and must not have side effects outside the transaction.
+func (d *LibSQLDatabase) RetryableTransaction(ctx context.Context, maxAttempts int, fn func(*sql.Tx) error) error {
+	return d.retryTransaction(ctx, maxAttempts, IsSnapshotConflict, fn)
+}
+
+// retryTransaction runs Transaction up to maxAttempts times while retryable(err) holds
+func (d *LibSQLDatabase) retryTransaction(ctx context.Context, maxAttempts int, retryable func(error) bool, fn func(*sql.Tx) error) error {
+	backoff := d.config.RetryBackoff
+
+	var err error
+	attempt := 0
+	for {
+		attempt++
+
+		err = d.Transaction(ctx, fn)
+		if err == nil {
+			return nil
+		}
+		if attempt >= maxAttempts || !retryable(err) {
+			break
+		}
+
+		d.logger.Warn("retrying transaction after transient error",
+			"attempt", attempt,
+			"backoff", backoff,
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("transaction cancelled after %d attempts: %w", attempt, errors.Join(err, ctx.Err()))
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	if attempt == 1 {
+		return err
+	}
+	return fmt.Errorf("transaction failed after %d attempts: %w", attempt, err)
+}
+
+// isRetryable reports whether err is a transient busy/locked or network error
+func isRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if IsBusy(err) {
+		return true
+	}
+
+	// Any other SQLite result code is a definite answer from the database
+	if _, ok := sqliteCode(err); ok {
+		return false
+	}
+
+	// A failover evicts the connection, so a fresh attempt reaches the new primary
+	if errors.Is(err, driver.ErrBadConn) || isFailover(err) {
+		return true
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	return errorContains(err, "connection reset", "broken pipe")
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"sync/atomic"
+)
+
+// rowsObserverKey carries a func(int) that receives the number of rows a query returned
+type rowsObserverKey struct{}
+
+// withRowsObserver asks the pooled connection to count rows returned by queries on ctx
+func withRowsObserver(ctx context.Context, observe func(int)) context.Context {
+	return context.WithValue(ctx, rowsObserverKey{}, observe)
+}
+
+// rowsReleaseKey carries a context.CancelFunc to run once the rows of a query are closed
+type rowsReleaseKey struct{}
+
+// withRowsRelease asks the pooled connection to call release when the rows returned by
+// a query on ctx are closed, so a deadline that must outlive the call still gets freed
+func withRowsRelease(ctx context.Context, release context.CancelFunc) context.Context {
+	return context.WithValue(ctx, rowsReleaseKey{}, release)
+}
+
+// trackedRows counts rows as they are read and, on Close, reports the total, releases
+// the statement timeout and drops out of the open rows count. Rows abandoned early report only what was read,
+// which is what the caller consumed.
+type trackedRows struct {
+	driver.Rows
+	n        int
+	observe  func(int)          // Receives the row count; may be nil
+	cancel   context.CancelFunc // Releases the statement timeout; may be nil
+	open     *atomic.Int64      // Open rows count to decrement on Close
+	reported bool
+}
+
+// Next reads the next row, counting it
+func (r *trackedRows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+	if err == nil {
+		r.n++
+	}
+	return err
+}
+
+// Close reports the row count, closes the driver rows and releases the timeout
+func (r *trackedRows) Close() error {
+	if !r.reported {
+		r.reported = true
+		r.open.Add(-1)
+		if r.observe != nil {
+			r.observe(r.n)
+		}
+	}
+
+	err := r.Rows.Close()
+	if r.cancel != nil {
+		r.cancel()
+	}
+	return err
+}
+
+// The wrapper hides the driver's optional column type interfaces, so forward them
+// with the same defaults database/sql uses when a driver lacks them
+
+// ColumnTypeScanType forwards to the driver
+func (r *trackedRows) ColumnTypeScanType(index int) reflect.Type {
+	if t, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return t.ColumnTypeScanType(index)
+	}
+	return reflect.TypeFor[any]()
+}
+
+// ColumnTypeDatabaseTypeName forwards to the driver
+func (r *trackedRows) ColumnTypeDatabaseTypeName(index int) string {
+	if t, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return t.ColumnTypeDatabaseTypeName(index)
+	}
+	return ""
+}
+
+// ColumnTypeNullable forwards to the driver
+func (r *trackedRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return t.ColumnTypeNullable(index)
+	}
+	return false, false
+}
+
+// ColumnTypeLength forwards to the driver
+func (r *trackedRows) ColumnTypeLength(index int) (length int64, ok bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return t.ColumnTypeLength(index)
+	}
+	return 0, false
+}
+
+// ColumnTypePrecisionScale forwards to the driver
+func (r *trackedRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return t.ColumnTypePrecisionScale(index)
+	}
+	return 0, 0, false
+}
+
+// HasNextResultSet forwards to the driver
+func (r *trackedRows) HasNextResultSet() bool {
+	if t, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return t.HasNextResultSet()
+	}
+	return false
+}
+
+// NextResultSet forwards to the driver
+func (r *trackedRows) NextResultSet() error {
+	if t, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return t.NextResultSet()
+	}
+	return io.EOF
+}
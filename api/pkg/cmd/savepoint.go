@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// identifierPattern matches plain SQL identifiers that are safe to interpolate
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WithSavepoint runs fn inside a named savepoint on tx, releasing it on success
+// and rolling back to it on error so the enclosing transaction can continue
+func (d *LibSQLDatabase) WithSavepoint(ctx context.Context, tx *sql.Tx, name string, fn func() error) error {
+	if !identifierPattern.MatchString(name) {
+		return fmt.Errorf("invalid savepoint name %q", name)
+	}
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to create savepoint %s: %w", name, err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			d.rollbackSavepoint(ctx, tx, name)
+			panic(p) // Re-panic after rollback
+		}
+	}()
+
+	if err := fn(); err != nil {
+		d.rollbackSavepoint(ctx, tx, name)
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// rollbackSavepoint undoes work since the savepoint and removes it from the stack
+func (d *LibSQLDatabase) rollbackSavepoint(ctx context.Context, tx *sql.Tx, name string) {
+	// ROLLBACK TO leaves the savepoint open, so release it afterwards
+	if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+		d.logger.Error("failed to rollback to savepoint", "savepoint", name, "error", err)
+		return
+	}
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		d.logger.Error("failed to release savepoint after rollback", "savepoint", name, "error", err)
+	}
+}
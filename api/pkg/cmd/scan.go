@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// QueryRowStruct runs query and scans the first row into a T, mapping columns to
+// fields by their `db:"col"` tag (or lowercased field name when untagged).
+// Fields tagged `db:"-"` are ignored. Returns ErrNoRows when no row matches.
+func QueryRowStruct[T any](ctx context.Context, d *LibSQLDatabase, query string, args ...any) (T, error) {
+	var result T
+
+	target := reflect.ValueOf(&result).Elem()
+	if target.Kind() != reflect.Struct {
+		return result, fmt.Errorf("QueryRowStruct requires a struct type, got %s", target.Type())
+	}
+
+	rows, err := d.Query(ctx, "query_row_struct", query, args...)
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return result, err
+		}
+		return result, ErrNoRows
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return result, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	if err := scanStruct(rows, columns, structFields(target.Type()), target); err != nil {
+		return result, err
+	}
+
+	return result, rows.Close()
+}
+
+// scanStruct scans the current row into target using the column-to-field mapping
+func scanStruct(rows *sql.Rows, columns []string, fields map[string][]int, target reflect.Value) error {
+	dest := make([]any, len(columns))
+	for i, col := range columns {
+		index, ok := fields[col]
+		if !ok {
+			return fmt.Errorf("column %q has no matching field in %s", col, target.Type())
+		}
+		dest[i] = target.FieldByIndex(index).Addr().Interface()
+	}
+
+	if err := rows.Scan(dest...); err != nil {
+		return fmt.Errorf("failed to scan into %s: %w", target.Type(), err)
+	}
+	return nil
+}
+
+// structFields maps column names to field indexes for the exported fields of t
+func structFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("db"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field.Index
+	}
+	return fields
+}
@@ -0,0 +1,215 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ColumnInfo describes a table column as reported by PRAGMA table_info
+type ColumnInfo struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	Default    sql.NullString // Default value expression, if any
+	PrimaryKey int            // 1-based position in the primary key, or 0 if not part of it
+}
+
+// IndexInfo describes an index as reported by PRAGMA index_list
+type IndexInfo struct {
+	Name    string
+	Unique  bool
+	Origin  string // "c" for CREATE INDEX, "u" for UNIQUE constraint, "pk" for PRIMARY KEY
+	Partial bool
+	Columns []string
+}
+
+// Tables lists user tables in the main schema
+func (d *LibSQLDatabase) Tables(ctx context.Context) ([]string, error) {
+	rows, err := d.db.QueryContext(ctx,
+		"SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan table name: %w", err)
+		}
+		tables = append(tables, name)
+	}
+
+	return tables, rows.Err()
+}
+
+// TableInfo describes a user table and the options it was created with
+type TableInfo struct {
+	Name         string
+	Strict       bool // Declared STRICT: column types are enforced
+	WithoutRowID bool // Declared WITHOUT ROWID: stored clustered on the primary key
+}
+
+// TableDetails lists user tables in the main schema along with their STRICT and
+// WITHOUT ROWID options, read from their CREATE TABLE statements
+func (d *LibSQLDatabase) TableDetails(ctx context.Context) ([]TableInfo, error) {
+	rows, err := d.db.QueryContext(ctx,
+		"SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []TableInfo
+	for rows.Next() {
+		var table TableInfo
+		var ddl string
+		if err := rows.Scan(&table.Name, &ddl); err != nil {
+			return nil, fmt.Errorf("failed to scan table: %w", err)
+		}
+		table.Strict, table.WithoutRowID = tableOptions(ddl)
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+// tableOptions parses the table options that follow the column list of a CREATE TABLE
+// statement. Options can't contain parentheses, so they are whatever follows the last
+// closing one. CREATE TABLE ... AS SELECT tables have no options.
+func tableOptions(ddl string) (strict, withoutRowID bool) {
+	end := strings.LastIndexByte(ddl, ')')
+	if end < 0 {
+		return false, false
+	}
+
+	for _, option := range strings.Split(stripSQLComments(ddl[end+1:]), ",") {
+		switch strings.Join(strings.Fields(strings.ToUpper(option)), " ") {
+		case "STRICT":
+			strict = true
+		case "WITHOUT ROWID":
+			withoutRowID = true
+		}
+	}
+	return strict, withoutRowID
+}
+
+// stripSQLComments removes -- and /* */ comments from a fragment with no string literals
+func stripSQLComments(s string) string {
+	var b strings.Builder
+	for len(s) > 0 {
+		switch {
+		case strings.HasPrefix(s, "--"):
+			end := strings.IndexByte(s, '\n')
+			if end < 0 {
+				return b.String()
+			}
+			s = s[end:]
+		case strings.HasPrefix(s, "/*"):
+			end := strings.Index(s[2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			b.WriteByte(' ')
+			s = s[end+4:]
+		default:
+			b.WriteByte(s[0])
+			s = s[1:]
+		}
+	}
+	return b.String()
+}
+
+// Columns describes the columns of table in declaration order
+func (d *LibSQLDatabase) Columns(ctx context.Context, table string) ([]ColumnInfo, error) {
+	rows, err := d.db.QueryContext(ctx,
+		"SELECT name, type, \"notnull\", dflt_value, pk FROM pragma_table_info(?) ORDER BY cid",
+		table,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnInfo
+	for rows.Next() {
+		var col ColumnInfo
+		if err := rows.Scan(&col.Name, &col.Type, &col.NotNull, &col.Default, &col.PrimaryKey); err != nil {
+			return nil, fmt.Errorf("failed to scan column of %s: %w", table, err)
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s not found", table)
+	}
+
+	return columns, nil
+}
+
+// Indexes describes the indexes on table, including their columns
+func (d *LibSQLDatabase) Indexes(ctx context.Context, table string) ([]IndexInfo, error) {
+	rows, err := d.db.QueryContext(ctx,
+		"SELECT name, \"unique\", origin, partial FROM pragma_index_list(?) ORDER BY seq",
+		table,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list indexes of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexInfo
+	for rows.Next() {
+		var idx IndexInfo
+		if err := rows.Scan(&idx.Name, &idx.Unique, &idx.Origin, &idx.Partial); err != nil {
+			return nil, fmt.Errorf("failed to scan index of %s: %w", table, err)
+		}
+		indexes = append(indexes, idx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range indexes {
+		columns, err := d.indexColumns(ctx, indexes[i].Name)
+		if err != nil {
+			return nil, err
+		}
+		indexes[i].Columns = columns
+	}
+
+	return indexes, nil
+}
+
+// indexColumns lists the columns of an index in key order.
+// Expression columns are reported as "<expr>".
+func (d *LibSQLDatabase) indexColumns(ctx context.Context, index string) ([]string, error) {
+	rows, err := d.db.QueryContext(ctx,
+		"SELECT COALESCE(name, '<expr>') FROM pragma_index_info(?) ORDER BY seqno",
+		index,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of index %s: %w", index, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan column of index %s: %w", index, err)
+		}
+		columns = append(columns, name)
+	}
+
+	return columns, rows.Err()
+}
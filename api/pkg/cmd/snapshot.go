@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// DBSnapshot is a point-in-time view of the database for status pages
+type DBSnapshot struct {
+	TakenAt         time.Time    `json:"taken_at"`
+	Pool            PoolSnapshot `json:"pool"`
+	SchemaVersion   int          `json:"schema_version"`
+	JournalMode     string       `json:"journal_mode,omitempty"`
+	ReadOnly        bool         `json:"read_only"`
+	BreakerState    string       `json:"breaker_state"`
+	StorageDegraded bool         `json:"storage_degraded"`
+
+	// Local file databases only
+	FileSizeBytes *int64 `json:"file_size_bytes,omitempty"`
+	WALSizeBytes  *int64 `json:"wal_size_bytes,omitempty"`
+
+	// Embedded replicas only
+	LastSync       *time.Time `json:"last_sync,omitempty"`
+	ReplicationLag string     `json:"replication_lag,omitempty"`
+}
+
+// PoolSnapshot mirrors sql.DBStats with JSON-friendly names and units, plus the open
+// rows and statement counts
+type PoolSnapshot struct {
+	MaxOpenConnections int   `json:"max_open_connections"`
+	TargetOpenConns    int   `json:"target_open_conns"`
+	OpenConnections    int   `json:"open_connections"`
+	InUse              int   `json:"in_use"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"wait_count"`
+	WaitDurationMs     int64 `json:"wait_duration_ms"`
+	MaxIdleClosed      int64 `json:"max_idle_closed"`
+	MaxIdleTimeClosed  int64 `json:"max_idle_time_closed"`
+	MaxLifetimeClosed  int64 `json:"max_lifetime_closed"`
+	OpenRows           int64 `json:"open_rows"`
+	OpenStatements     int64 `json:"open_statements"`
+}
+
+// Snapshot gathers pool stats, schema version and, where they apply, file sizes and
+// replica sync state into a JSON-serializable struct
+func (d *LibSQLDatabase) Snapshot(ctx context.Context) (*DBSnapshot, error) {
+	stats := d.db.Stats()
+	snap := &DBSnapshot{
+		TakenAt: time.Now(),
+		Pool: PoolSnapshot{
+			MaxOpenConnections: stats.MaxOpenConnections,
+			TargetOpenConns:    d.PoolTarget(),
+			OpenConnections:    stats.OpenConnections,
+			InUse:              stats.InUse,
+			Idle:               stats.Idle,
+			WaitCount:          stats.WaitCount,
+			WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+			MaxIdleClosed:      stats.MaxIdleClosed,
+			MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+			MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+			OpenRows:           d.OpenRows(),
+			OpenStatements:     d.OpenStatements(),
+		},
+		JournalMode:     d.config.JournalMode,
+		ReadOnly:        d.config.ReadOnly,
+		BreakerState:    d.BreakerState().String(),
+		StorageDegraded: d.StorageDegraded(),
+	}
+
+	version, err := d.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	snap.SchemaVersion = version
+
+	if isLocalFile(d.config.URL) {
+		path := localPath(d.config.URL)
+		if snap.FileSizeBytes, err = fileSize(path); err != nil {
+			return nil, err
+		}
+		if snap.WALSizeBytes, err = fileSize(path + "-wal"); err != nil {
+			return nil, err
+		}
+	}
+
+	if isEmbeddedReplica(d.config) {
+		lastSync := time.Unix(0, d.lastSync.Load())
+		snap.LastSync = &lastSync
+		snap.ReplicationLag = time.Since(lastSync).Round(time.Millisecond).String()
+	}
+
+	return snap, nil
+}
+
+// fileSize returns the size of path, or nil if it doesn't exist (such as a WAL that
+// has been checkpointed away)
+func fileSize(path string) (*int64, error) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+
+	size := info.Size()
+	return &size, nil
+}
@@ -0,0 +1,35 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// softDeleteColumn is the timestamp column marking a row as soft-deleted
+const softDeleteColumn = "deleted_at"
+
+// SoftDelete marks the row identified by idColumn = id as deleted by setting deleted_at.
+// Rows that are already deleted are left untouched; ErrNoRows is returned when no live
+// row matched.
+func (d *LibSQLDatabase) SoftDelete(ctx context.Context, table, idColumn string, id any) error {
+	query := fmt.Sprintf("UPDATE %s SET %s = CURRENT_TIMESTAMP WHERE %s = ? AND %s IS NULL",
+		quoteIdent(table),
+		quoteIdent(softDeleteColumn),
+		quoteIdent(idColumn),
+		quoteIdent(softDeleteColumn),
+	)
+
+	res, err := d.ExecContext(ctx, "soft_delete", query, id)
+	if err != nil {
+		return fmt.Errorf("failed to soft delete from %s: %w", table, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to read soft delete result: %w", err)
+	}
+	if n == 0 {
+		return ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package database
+
+import "strings"
+
+// splitStatements splits a SQL script into individual statements on semicolons.
+// Semicolons inside string literals, quoted identifiers, comments and the
+// BEGIN...END body of CREATE TRIGGER are not treated as separators.
+// Statements containing only whitespace or comments are dropped.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		start      int  // Start of the current statement
+		hasContent bool // Current statement has more than whitespace and comments
+		words      int  // Keywords seen in the current statement
+		trigger    bool // Current statement is CREATE [TEMP] TRIGGER
+		depth      int  // BEGIN/CASE ... END nesting inside a trigger
+	)
+
+	flush := func(end int) {
+		if hasContent {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start = end + 1
+		hasContent, words, trigger, depth = false, 0, false, 0
+	}
+
+	for i := 0; i < len(script); {
+		c := script[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			// Quoted literal or identifier; a doubled quote is an escaped quote
+			j := i + 1
+			for j < len(script) {
+				if script[j] == c {
+					if j+1 < len(script) && script[j+1] == c {
+						j += 2
+						continue
+					}
+					break
+				}
+				j++
+			}
+			hasContent = true
+			i = j + 1
+
+		case c == '[':
+			j := strings.IndexByte(script[i:], ']')
+			if j < 0 {
+				j = len(script) - i
+			}
+			hasContent = true
+			i += j + 1
+
+		case c == '-' && i+1 < len(script) && script[i+1] == '-':
+			j := strings.IndexByte(script[i:], '\n')
+			if j < 0 {
+				j = len(script) - i
+			}
+			i += j
+
+		case c == '/' && i+1 < len(script) && script[i+1] == '*':
+			j := strings.Index(script[i+2:], "*/")
+			if j < 0 {
+				i = len(script)
+			} else {
+				i += j + 4
+			}
+
+		case isIdentStart(c):
+			j := i + 1
+			for j < len(script) && isIdentPart(script[j]) {
+				j++
+			}
+			word := strings.ToUpper(script[i:j])
+			hasContent = true
+			words++
+
+			switch {
+			case words <= 3 && word == "TRIGGER":
+				trigger = true
+			case trigger && (word == "BEGIN" || word == "CASE"):
+				depth++
+			case trigger && word == "END" && depth > 0:
+				depth--
+			}
+			i = j
+
+		case c == ';' && depth == 0:
+			flush(i)
+			i++
+
+		default:
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				hasContent = true
+			}
+			i++
+		}
+	}
+
+	if start < len(script) {
+		flush(len(script))
+	}
+
+	return statements
+}
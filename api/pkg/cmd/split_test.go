@@ -0,0 +1,105 @@
+package database
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "simple",
+			script: "CREATE TABLE a (id INTEGER); INSERT INTO a VALUES (1);",
+			want:   []string{"CREATE TABLE a (id INTEGER)", "INSERT INTO a VALUES (1)"},
+		},
+		{
+			name:   "no trailing semicolon",
+			script: "SELECT 1; SELECT 2",
+			want:   []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:   "empty statements",
+			script: ";;  ;SELECT 1;;",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "semicolon in string",
+			script: "INSERT INTO a VALUES ('x;y'); SELECT 1;",
+			want:   []string{"INSERT INTO a VALUES ('x;y')", "SELECT 1"},
+		},
+		{
+			name:   "escaped quote in string",
+			script: "INSERT INTO a VALUES ('it''s;'); SELECT 1",
+			want:   []string{"INSERT INTO a VALUES ('it''s;')", "SELECT 1"},
+		},
+		{
+			name:   "quoted identifiers",
+			script: "SELECT \"a;b\", `c;d` FROM [e;f]; SELECT 1",
+			want:   []string{"SELECT \"a;b\", `c;d` FROM [e;f]", "SELECT 1"},
+		},
+		{
+			name:   "unterminated string",
+			script: "SELECT 'abc; SELECT 2",
+			want:   []string{"SELECT 'abc; SELECT 2"},
+		},
+		{
+			name:   "line comment",
+			script: "SELECT 1; -- done; really\nSELECT 2;",
+			want:   []string{"SELECT 1", "-- done; really\nSELECT 2"},
+		},
+		{
+			name:   "block comment",
+			script: "SELECT /* a; b */ 1;",
+			want:   []string{"SELECT /* a; b */ 1"},
+		},
+		{
+			name:   "comment-only statements dropped",
+			script: "-- header\n; SELECT 1;\n/* trailer; */\n-- end\n",
+			want:   []string{"SELECT 1"},
+		},
+		{
+			name:   "trigger body",
+			script: "CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = n + 1; INSERT INTO c VALUES (1); END; SELECT 1;",
+			want: []string{
+				"CREATE TRIGGER t AFTER INSERT ON a BEGIN UPDATE b SET n = n + 1; INSERT INTO c VALUES (1); END",
+				"SELECT 1",
+			},
+		},
+		{
+			name:   "temp trigger with CASE",
+			script: "CREATE TEMP TRIGGER t BEFORE DELETE ON a BEGIN SELECT CASE WHEN old.id = 1 THEN RAISE(ABORT, 'no; way') END; END; SELECT 2",
+			want: []string{
+				"CREATE TEMP TRIGGER t BEFORE DELETE ON a BEGIN SELECT CASE WHEN old.id = 1 THEN RAISE(ABORT, 'no; way') END; END",
+				"SELECT 2",
+			},
+		},
+		{
+			name:   "lowercase trigger",
+			script: "create trigger t after insert on a begin delete from b; end;\nselect 1",
+			want:   []string{"create trigger t after insert on a begin delete from b; end", "select 1"},
+		},
+		{
+			name:   "transaction BEGIN is not a trigger",
+			script: "BEGIN; SELECT 1; COMMIT;",
+			want:   []string{"BEGIN", "SELECT 1", "COMMIT"},
+		},
+		{
+			name:   "drop trigger",
+			script: "DROP TRIGGER IF EXISTS t; SELECT 1",
+			want:   []string{"DROP TRIGGER IF EXISTS t", "SELECT 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitStatements(tt.script)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("splitStatements(%q)\n got %q\nwant %q", tt.script, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// stmtCache is an LRU cache of prepared statements keyed on query text
+type stmtCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Front is most recently used
+	entries map[string]*list.Element
+}
+
+// stmtEntry is a single cached statement. refs counts callers between Prepare and
+// release; a statement that is evicted while in use is closed by the last release.
+type stmtEntry struct {
+	query   string
+	stmt    *sql.Stmt
+	refs    int
+	evicted bool
+}
+
+// newStmtCache creates a statement cache holding at most size statements
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Prepare returns a cached prepared statement for query, preparing it on first use,
+// and a release func the caller must call once done with it. The statement is owned by
+// the cache: callers must not Close it, and it stays open until released even if it is
+// evicted in the meantime. Calling release more than once is harmless.
+func (d *LibSQLDatabase) Prepare(ctx context.Context, query string) (*sql.Stmt, func(), error) {
+	if entry := d.stmts.get(query); entry != nil {
+		d.observeStmtCache("hit")
+		return entry.stmt, d.stmts.releaser(entry), nil
+	}
+	d.observeStmtCache("miss")
+
+	stmt, err := d.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+
+	entry := d.stmts.add(query, stmt)
+	return entry.stmt, d.stmts.releaser(entry), nil
+}
+
+// observeStmtCache records a statement cache hit or miss
+func (d *LibSQLDatabase) observeStmtCache(result string) {
+	if d.metrics == nil {
+		return
+	}
+	d.metrics.stmtCache.WithLabelValues(result).Inc()
+}
+
+// get returns the cached entry for query, marks it recently used and takes a reference
+func (c *stmtCache) get(query string) *stmtEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[query]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	entry := elem.Value.(*stmtEntry)
+	entry.refs++
+	return entry
+}
+
+// add caches stmt for query and takes a reference, evicting the least recently used
+// statement when full. If another caller cached the same query first, stmt is closed and
+// the existing entry returned.
+func (c *stmtCache) add(query string, stmt *sql.Stmt) *stmtEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[query]; ok {
+		stmt.Close()
+		c.order.MoveToFront(elem)
+		entry := elem.Value.(*stmtEntry)
+		entry.refs++
+		return entry
+	}
+
+	entry := &stmtEntry{query: query, stmt: stmt, refs: 1}
+	c.entries[query] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.evict(c.order.Remove(oldest).(*stmtEntry))
+	}
+
+	return entry
+}
+
+// releaser returns a func that releases entry's reference exactly once, however many
+// times it is called
+func (c *stmtCache) releaser(entry *stmtEntry) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() { c.release(entry) })
+	}
+}
+
+// release drops a reference taken by get or add, closing the statement if it was evicted
+// and this was the last one
+func (c *stmtCache) release(entry *stmtEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+// evict removes entry from the index and closes its statement unless it is still in use.
+// c.mu must be held.
+func (c *stmtCache) evict(entry *stmtEntry) {
+	delete(c.entries, entry.query)
+	entry.evicted = true
+	if entry.refs == 0 {
+		entry.stmt.Close()
+	}
+}
+
+// closeAll removes every cached statement, closing those not in use now and the rest
+// when they are released
+func (c *stmtCache) closeAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, elem := range c.entries {
+		c.evict(elem.Value.(*stmtEntry))
+	}
+	c.order.Init()
+}
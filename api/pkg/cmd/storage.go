@@ -0,0 +1,40 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// ensureDataDir creates the parent directory of dbPath if it does not exist
+func ensureDataDir(dbPath string) error {
+	dir := filepath.Dir(dbPath)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		if errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("%w: cannot create %s: %w", ErrReadOnlyStorage, dir, err)
+		}
+		return fmt.Errorf("failed to create data directory %s: %w", dir, err)
+	}
+	return nil
+}
+
+// checkWritable verifies that the directory holding dbPath accepts new files.
+// SQLite needs this for its journal and WAL files even if the database exists.
+func checkWritable(dbPath string) error {
+	dir := filepath.Dir(dbPath)
+
+	probe, err := os.CreateTemp(dir, ".write-probe-*")
+	if err != nil {
+		if errors.Is(err, syscall.EROFS) || errors.Is(err, fs.ErrPermission) {
+			return fmt.Errorf("%w: cannot write to %s: %w", ErrReadOnlyStorage, dir, err)
+		}
+		return fmt.Errorf("failed to check data directory %s: %w", dir, err)
+	}
+
+	probe.Close()
+	os.Remove(probe.Name())
+	return nil
+}
@@ -0,0 +1,110 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// storageFullThreshold is how many consecutive storage errors on writes switch the
+// database into degraded read-only mode
+const storageFullThreshold = 3
+
+// storageGuard tracks storage errors on writes. While degraded, writes fail fast with
+// ErrStorageFull instead of retrying against a full disk, and reads keep working.
+type storageGuard struct {
+	failures atomic.Int32
+	degraded atomic.Bool
+}
+
+// StorageDegraded reports whether writes are disabled because storage is full
+func (d *LibSQLDatabase) StorageDegraded() bool {
+	return d.storage.degraded.Load()
+}
+
+// ClearStorageFull re-enables writes after an operator has freed space. The health
+// monitor clears the state on its own when a probe write succeeds.
+func (d *LibSQLDatabase) ClearStorageFull() {
+	d.storage.failures.Store(0)
+	if d.storage.degraded.CompareAndSwap(true, false) {
+		d.setStorageDegraded(false)
+		d.logger.Info("storage recovered, writes re-enabled")
+	}
+}
+
+// allowWrite fails fast with ErrStorageFull while degraded
+func (d *LibSQLDatabase) allowWrite() error {
+	if d.storage.degraded.Load() {
+		return ErrStorageFull
+	}
+	return nil
+}
+
+// recordWrite feeds a write result into the storage guard
+func (d *LibSQLDatabase) recordWrite(err error) {
+	if err == nil {
+		d.storage.failures.Store(0)
+		return
+	}
+	if !isStorageError(err) {
+		return
+	}
+
+	if d.storage.failures.Add(1) < storageFullThreshold {
+		return
+	}
+	if !d.storage.degraded.CompareAndSwap(false, true) {
+		return
+	}
+
+	d.setStorageDegraded(true)
+	d.logger.Error("storage is full or failing, disabling writes",
+		"consecutive_failures", d.storage.failures.Load(),
+		"auto_recovery", d.config.HealthCheckInterval > 0,
+		"error", err,
+	)
+}
+
+// probeStorage attempts a real write to the main database and clears the degraded
+// state if it succeeds. Rewriting user_version with its own value dirties the header
+// page without changing anything the application can see.
+func (d *LibSQLDatabase) probeStorage(ctx context.Context) {
+	if err := d.storageProbe(ctx); err != nil {
+		d.logger.Debug("storage probe failed, writes stay disabled", "error", err)
+		return
+	}
+	d.ClearStorageFull()
+}
+
+// storageProbe performs the write used by probeStorage
+func (d *LibSQLDatabase) storageProbe(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.HealthTimeout)
+	defer cancel()
+
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin storage probe: %w", err)
+	}
+	defer tx.Rollback()
+
+	var version int
+	if err := tx.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read user_version: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version=%d", version)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setStorageDegraded updates the degraded gauge
+func (d *LibSQLDatabase) setStorageDegraded(degraded bool) {
+	if d.metrics == nil {
+		return
+	}
+	if degraded {
+		d.metrics.storageDegraded.Set(1)
+	} else {
+		d.metrics.storageDegraded.Set(0)
+	}
+}
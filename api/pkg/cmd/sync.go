@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Sync forces an immediate sync of the embedded replica from the remote primary.
+// go-libsql's sync can't be interrupted, so ctx is only checked before it starts.
+func (d *LibSQLDatabase) Sync(ctx context.Context) error {
+	if !isEmbeddedReplica(d.config) {
+		return ErrSyncNotEnabled
+	}
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("failed to sync replica: %w", err)
+	}
+
+	replicated, err := d.connector.replica.Sync()
+	if err != nil {
+		return fmt.Errorf("failed to sync replica: %w", redactError(err, d.config, ""))
+	}
+
+	d.lastSync.Store(time.Now().UnixNano())
+	d.logger.Debug("replica synced",
+		"frame_no", replicated.FrameNo,
+		"frames_synced", replicated.FramesSynced,
+	)
+	return nil
+}
+
+// ReplicationLag reports how long it has been since the embedded replica last synced
+func (d *LibSQLDatabase) ReplicationLag(ctx context.Context) (time.Duration, error) {
+	if !isEmbeddedReplica(d.config) {
+		return 0, ErrSyncNotEnabled
+	}
+	return time.Since(time.Unix(0, d.lastSync.Load())), nil
+}
+
+// syncLoop periodically syncs the embedded replica on SyncInterval
+func (d *LibSQLDatabase) syncLoop(ctx context.Context) {
+	ticker := time.NewTicker(d.config.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := d.Sync(ctx); err != nil {
+				d.logger.Warn("background replica sync failed", "error", err)
+			}
+		}
+	}
+}
+
+// isEmbeddedReplica reports whether the config describes a local replica of a remote primary
+func isEmbeddedReplica(cfg LibSQLConfig) bool {
+	return isLocalFile(cfg.URL) && cfg.SyncURL != ""
+}
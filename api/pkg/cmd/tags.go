@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryTagsKey holds the []queryTag attached to a context
+type queryTagsKey struct{}
+
+// queryTag is a key-value pair attached to queries for correlation
+type queryTag struct {
+	key   string
+	value string
+}
+
+// WithQueryTag returns a context whose queries carry the tag in slow-query and error
+// logs and, when tracing is enabled, as a db.tag.<key> span attribute. Use it to thread
+// request or command IDs through to the database layer. Setting a key again replaces it.
+func WithQueryTag(ctx context.Context, key, value string) context.Context {
+	existing, _ := ctx.Value(queryTagsKey{}).([]queryTag)
+
+	// Copy so contexts derived from the same parent don't share a backing array
+	tags := slices.DeleteFunc(slices.Clone(existing), func(t queryTag) bool { return t.key == key })
+	tags = append(tags, queryTag{key: key, value: value})
+	return context.WithValue(ctx, queryTagsKey{}, tags)
+}
+
+// logTags returns the context's query tags and trace ID as logger key-value args
+func logTags(ctx context.Context) []any {
+	tags, _ := ctx.Value(queryTagsKey{}).([]queryTag)
+
+	args := make([]any, 0, 2*len(tags)+2)
+	for _, t := range tags {
+		args = append(args, t.key, t.value)
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		args = append(args, "trace_id", sc.TraceID().String())
+	}
+	return args
+}
+
+// spanTags returns the context's query tags as span attributes
+func spanTags(ctx context.Context) []attribute.KeyValue {
+	tags, _ := ctx.Value(queryTagsKey{}).([]queryTag)
+
+	attrs := make([]attribute.KeyValue, len(tags))
+	for i, t := range tags {
+		attrs[i] = attribute.String("db.tag."+t.key, t.value)
+	}
+	return attrs
+}
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan starts a client span for a database operation when tracing is enabled.
+// The returned span is nil when no Tracer is configured.
+func (d *LibSQLDatabase) startSpan(ctx context.Context, name, query string) (context.Context, trace.Span) {
+	if d.config.Tracer == nil {
+		return ctx, nil
+	}
+
+	attrs := []attribute.KeyValue{attribute.String("db.system", "sqlite")}
+	if query != "" && !d.config.RedactStatements {
+		attrs = append(attrs, attribute.String("db.statement", query))
+	}
+	attrs = append(attrs, spanTags(ctx)...)
+
+	return d.config.Tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// endSpan records the outcome of an operation on span and ends it
+func endSpan(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else {
+		span.SetStatus(codes.Ok, "")
+	}
+	span.End()
+}
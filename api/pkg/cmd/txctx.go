@@ -0,0 +1,40 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Execer is the query surface shared by *sql.DB, *sql.Tx and *sql.Conn, so code can
+// run the same statements with or without a transaction
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// txKey carries the *sql.Tx stored by WithTx
+type txKey struct{}
+
+// WithTx returns a context carrying tx, which RunInTx calls made with it will join
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the transaction stored by WithTx, if any
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok && tx != nil
+}
+
+// RunInTx runs fn inside the transaction carried by ctx, or inside a new transaction
+// when there is none. A joined transaction is left for its owner to commit or roll
+// back; an error from fn is simply returned so the owner sees it.
+func (d *LibSQLDatabase) RunInTx(ctx context.Context, fn func(exec Execer) error) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		return fn(tx)
+	}
+	return d.Transaction(ctx, func(tx *sql.Tx) error {
+		return fn(tx)
+	})
+}
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// maxTxOriginFrames bounds the stack captured for each transaction
+const maxTxOriginFrames = 16
+
+// packagePrefix identifies frames inside this package so they can be skipped
+var packagePrefix = func() string {
+	pc, _, _, _ := runtime.Caller(0)
+	name := runtime.FuncForPC(pc).Name()
+	// Keep "module/path/pkg." and drop the function name after it
+	slash := strings.LastIndex(name, "/")
+	dot := slash + 1 + strings.Index(name[slash+1:], ".")
+	return name[:dot+1]
+}()
+
+// txOrigin captures the stack of the caller starting a transaction, skipping frames in
+// this package. It returns "" when MaxTxDuration is disabled so the cost is only paid
+// when the warning can fire.
+func (d *LibSQLDatabase) txOrigin() string {
+	if d.config.MaxTxDuration <= 0 {
+		return ""
+	}
+
+	pcs := make([]uintptr, maxTxOriginFrames)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var sb strings.Builder
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, packagePrefix) {
+			sb.WriteString(frame.Function)
+			sb.WriteString("\n\t")
+			sb.WriteString(frame.File)
+			sb.WriteString(":")
+			sb.WriteString(strconv.Itoa(frame.Line))
+			sb.WriteString("\n")
+		}
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Upsert inserts values into table, or on a conflict over conflictCols updates every
+// other column from the new values. When every column is a conflict column the insert
+// becomes DO NOTHING. Returns the number of rows inserted or updated.
+func (d *LibSQLDatabase) Upsert(ctx context.Context, table string, conflictCols []string, values map[string]any) (int64, error) {
+	if len(values) == 0 {
+		return 0, fmt.Errorf("upsert into %s: no values given", table)
+	}
+	if len(conflictCols) == 0 {
+		return 0, fmt.Errorf("upsert into %s: no conflict columns given", table)
+	}
+	for _, col := range conflictCols {
+		if _, ok := values[col]; !ok {
+			return 0, fmt.Errorf("upsert into %s: conflict column %q has no value", table, col)
+		}
+	}
+
+	// Sort for a stable statement text, which keeps the statement cache and logs useful
+	columns := make([]string, 0, len(values))
+	for col := range values {
+		columns = append(columns, col)
+	}
+	sort.Strings(columns)
+
+	quoted := make([]string, len(columns))
+	args := make([]any, len(columns))
+	var updates []string
+	for i, col := range columns {
+		quoted[i] = quoteIdent(col)
+		args[i] = values[col]
+		if !slices.Contains(conflictCols, col) {
+			updates = append(updates, fmt.Sprintf("%s = excluded.%s", quoted[i], quoted[i]))
+		}
+	}
+
+	conflict := make([]string, len(conflictCols))
+	for i, col := range conflictCols {
+		conflict[i] = quoteIdent(col)
+	}
+
+	action := "DO NOTHING"
+	if len(updates) > 0 {
+		action = "DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) %s",
+		quoteIdent(table),
+		strings.Join(quoted, ", "),
+		strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", "),
+		strings.Join(conflict, ", "),
+		action,
+	)
+
+	n, err := d.Exec(ctx, "upsert", query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("upsert into %s: %w", table, err)
+	}
+	return n, nil
+}
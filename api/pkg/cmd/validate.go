@@ -0,0 +1,107 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Validate checks the configuration and reports every problem found at once, joined
+// into a single error. Zero values are fine wherever a default applies.
+func (c LibSQLConfig) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.URL == "" {
+		errs = append(errs, ErrNoURL)
+	}
+
+	if c.MaxOpenConns < 0 {
+		add("MaxOpenConns must not be negative, got %d", c.MaxOpenConns)
+	}
+	if c.MaxIdleConns < 0 {
+		add("MaxIdleConns must not be negative, got %d", c.MaxIdleConns)
+	}
+	if c.idleExceedsOpen() && c.StrictPoolLimits {
+		add("MaxIdleConns (%d) exceeds MaxOpenConns (%d)", c.MaxIdleConns, c.MaxOpenConns)
+	}
+	if c.MinOpenConns < 0 {
+		add("MinOpenConns must not be negative, got %d", c.MinOpenConns)
+	}
+	if c.AutoTunePool && c.MaxOpenConns == 0 {
+		add("AutoTunePool requires MaxOpenConns to bound the pool")
+	}
+	if c.AutoTunePool && c.MinOpenConns > c.MaxOpenConns {
+		add("MinOpenConns (%d) exceeds MaxOpenConns (%d)", c.MinOpenConns, c.MaxOpenConns)
+	}
+	if c.WarmupConns < 0 {
+		add("WarmupConns must not be negative, got %d", c.WarmupConns)
+	}
+	if c.WarmupConns > c.MaxOpenConns && c.MaxOpenConns > 0 {
+		add("WarmupConns (%d) exceeds MaxOpenConns (%d)", c.WarmupConns, c.MaxOpenConns)
+	}
+	if c.MaxRetries < 0 {
+		add("MaxRetries must not be negative, got %d", c.MaxRetries)
+	}
+	if c.BreakerThreshold < 0 {
+		add("BreakerThreshold must not be negative, got %d", c.BreakerThreshold)
+	}
+	if c.CacheSizeKB < 0 {
+		add("CacheSizeKB must not be negative, got %d", c.CacheSizeKB)
+	}
+	if c.MmapSizeBytes < 0 {
+		add("MmapSizeBytes must not be negative, got %d", c.MmapSizeBytes)
+	}
+
+	durations := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"ConnMaxLifetime", c.ConnMaxLifetime},
+		{"ConnMaxIdleTime", c.ConnMaxIdleTime},
+		{"ConnMaxLifetimeJitter", c.ConnMaxLifetimeJitter},
+		{"SyncInterval", c.SyncInterval},
+		{"RetryBackoff", c.RetryBackoff},
+		{"CheckpointInterval", c.CheckpointInterval},
+		{"SlowQueryThreshold", c.SlowQueryThreshold},
+		{"MaxReplicationLag", c.MaxReplicationLag},
+		{"BusyTimeout", c.BusyTimeout},
+		{"HealthTimeout", c.HealthTimeout},
+		{"ConnectTimeout", c.ConnectTimeout},
+		{"MaxQueryDuration", c.MaxQueryDuration},
+		{"ConnAcquireWarnThreshold", c.ConnAcquireWarnThreshold},
+		{"HealthCheckInterval", c.HealthCheckInterval},
+		{"BreakerCooldown", c.BreakerCooldown},
+		{"MaxTxDuration", c.MaxTxDuration},
+		{"OptimizeInterval", c.OptimizeInterval},
+		{"StatementTimeout", c.StatementTimeout},
+		{"IdleCheckpointDelay", c.IdleCheckpointDelay},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			add("%s must not be negative, got %s", d.name, d.value)
+		}
+	}
+
+	if _, err := resolveJournalMode(c); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Embedded replicas are file URLs that use the token to sync from SyncURL
+	if c.AuthToken != "" && isLocalFile(c.URL) && c.SyncURL == "" {
+		add("AuthToken is set for a local file URL without SyncURL; it would be ignored")
+	}
+	if c.SyncURL != "" && !isLocalFile(c.URL) {
+		add("SyncURL requires a file: URL for the embedded replica")
+	}
+
+	return errors.Join(errs...)
+}
+
+// idleExceedsOpen reports whether database/sql will silently clamp MaxIdleConns down to
+// MaxOpenConns. Unless StrictPoolLimits is set this only earns a warning at startup.
+func (c LibSQLConfig) idleExceedsOpen() bool {
+	return c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// VectorMatch is a row returned by VectorSearch with its cosine distance from the query
+type VectorMatch struct {
+	RowID    int64
+	Distance float64 // 0 is identical, 2 is opposite
+}
+
+// VectorSearch returns the k rows of table whose F32_BLOB column is nearest to query by
+// cosine distance, closest first. Join the returned row IDs back to table for the data.
+func (d *LibSQLDatabase) VectorSearch(ctx context.Context, table, column string, query []float32, k int) ([]VectorMatch, error) {
+	if len(query) == 0 {
+		return nil, fmt.Errorf("vector search requires a non-empty query vector")
+	}
+	if k <= 0 {
+		return nil, fmt.Errorf("vector search requires a positive k, got %d", k)
+	}
+
+	stmt := fmt.Sprintf(
+		"SELECT rowid, vector_distance_cos(%s, vector(?)) AS distance FROM %s ORDER BY distance LIMIT ?",
+		quoteIdent(column), quoteIdent(table),
+	)
+
+	rows, err := d.Query(ctx, "vector_search", stmt, encodeVector(query), k)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run vector search on %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	matches := make([]VectorMatch, 0, k)
+	for rows.Next() {
+		var m VectorMatch
+		if err := rows.Scan(&m.RowID, &m.Distance); err != nil {
+			return nil, fmt.Errorf("failed to scan vector match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vector matches: %w", err)
+	}
+
+	return matches, nil
+}
+
+// encodeVector packs v as little-endian float32s, the F32_BLOB storage format
+func encodeVector(v []float32) []byte {
+	buf := make([]byte, 4*len(v))
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(f))
+	}
+	return buf
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// warmup opens and pings WarmupConns connections, then returns them to the idle pool.
+// Individual failures are logged; warmup only fails if no connection could be opened.
+func warmup(ctx context.Context, db *sql.DB, cfg LibSQLConfig, logger Logger) error {
+	n := cfg.WarmupConns
+	if cfg.MaxOpenConns > 0 && n > cfg.MaxOpenConns {
+		n = cfg.MaxOpenConns
+	}
+
+	// Hold every connection until all are open so the pool can't hand back the same one
+	conns := make([]*sql.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	var lastErr error
+	for i := 0; i < n; i++ {
+		conn, err := db.Conn(ctx)
+		if err == nil {
+			err = conn.PingContext(ctx)
+			if err != nil {
+				conn.Close()
+			}
+		}
+		if err != nil {
+			logger.Warn("failed to warm up connection", "index", i, "error", err)
+			lastErr = err
+			continue
+		}
+		conns = append(conns, conn)
+	}
+
+	if len(conns) == 0 {
+		return fmt.Errorf("failed to warm up any connections: %w", lastErr)
+	}
+
+	if cfg.MaxIdleConns < len(conns) {
+		logger.Warn("warmed connections exceed MaxIdleConns; extras will be closed",
+			"warmed", len(conns),
+			"max_idle_conns", cfg.MaxIdleConns,
+		)
+	}
+
+	logger.Debug("connection pool warmed up", "connections", len(conns))
+	return nil
+}
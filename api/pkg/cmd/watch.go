@@ -0,0 +1,66 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// newFileWatcher watches the directory holding the local database. The directory is
+// watched rather than the files because SQLite deletes and recreates the -wal file.
+func newFileWatcher(dbPath string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(dbPath)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", filepath.Dir(dbPath), err)
+	}
+	return watcher, nil
+}
+
+// watchDataChanges calls OnDataChanged once writes to the database file or its WAL
+// have been quiet for WatchDebounce. Writes made by this process fire it too.
+func (d *LibSQLDatabase) watchDataChanges(ctx context.Context, watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	dbPath, err := filepath.Abs(localPath(d.config.URL))
+	if err != nil {
+		d.logger.Warn("failed to resolve database path for watching", "error", err)
+		return
+	}
+	watched := map[string]bool{dbPath: true, dbPath + "-wal": true}
+
+	debounce := time.NewTimer(0)
+	if !debounce.Stop() {
+		<-debounce.C
+	}
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			name, err := filepath.Abs(event.Name)
+			if err != nil || !watched[name] || !event.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+			debounce.Reset(d.config.WatchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			d.logger.Warn("database file watcher error", "error", err)
+		case <-debounce.C:
+			d.config.OnDataChanged()
+		}
+	}
+}
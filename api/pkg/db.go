@@ -23,9 +23,9 @@ func InitializeDatabase(cfg *config.DatabaseConfig, logger *slog.Logger) (*LibSQ
 		MigrationPath:   cfg.MigrationPath,
 	}
 
-	// Validate configuration
-	if dbConfig.URL == "" {
-		return nil, fmt.Errorf("database URL is required")
+	// Validate configuration; a missing URL matches ErrNoURL with errors.Is
+	if err := dbConfig.Validate(); err != nil {
+		return nil, err
 	}
 
 	// Set defaults if not provided